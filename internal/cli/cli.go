@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -30,8 +31,9 @@ const (
 var version = "dev"
 
 const (
-	stdinArg  = "-"
-	emptyPath = ""
+	stdinArg   = "-"
+	emptyPath  = ""
+	currentDir = "."
 )
 
 const (
@@ -46,7 +48,8 @@ const (
 )
 
 const (
-	indexFirst = 0
+	indexFirst  = 0
+	indexSecond = 1
 )
 
 const formattedFilePerm = 0o644
@@ -61,6 +64,15 @@ const (
 	flagHelp      = "help"
 )
 
+const (
+	flagConfig          = "config"
+	flagStyle           = "style"
+	flagBlockOrder      = "block-order"
+	flagAttributeOrder  = "attribute-order"
+	flagTopLevelSpacing = "top-level-spacing"
+	flagEOFNewline      = "eof-newline"
+)
+
 const (
 	diffCommand     = "diff"
 	diffErrorFormat = "Failed to generate diff for %s: %s"
@@ -102,6 +114,24 @@ func (err diffError) Error() string {
 	return fmt.Sprintf(diffErrorFormat, err.path, err.cause)
 }
 
+type styleError struct {
+	style string
+}
+
+// Error returns the error string.
+func (err styleError) Error() string {
+	return fmt.Sprintf(
+		"invalid argument %q for \"--%s\" flag: use one of %s",
+		err.style,
+		flagStyle,
+		strings.Join(config.StyleNames(), ", "),
+	)
+}
+
+func invalidStyleError(style string) error {
+	return styleError{style: style}
+}
+
 type diagError struct {
 	message string
 }
@@ -120,15 +150,28 @@ const (
 )
 
 type fmtOptions struct {
-	list      bool
-	write     bool
-	diff      bool
-	check     bool
-	noColor   bool
-	recursive bool
+	list       bool
+	write      bool
+	diff       bool
+	check      bool
+	noColor    bool
+	recursive  bool
+	configPath string
+	// overrides are applied after configuration files, lowest
+	// precedence first.
+	overrides []config.Settings
 	targets   []string
 }
 
+// ruleFlags holds the values of the formatting rule flags.
+type ruleFlags struct {
+	style           string
+	blockOrder      bool
+	attributeOrder  bool
+	topLevelSpacing bool
+	eofNewline      bool
+}
+
 type ioConfig struct {
 	in  io.Reader
 	out io.Writer
@@ -154,14 +197,31 @@ func (cw colorlessWriter) Write(p []byte) (int, error) {
 
 // Execute runs the terraformat CLI and returns the exit code.
 func Execute() int {
-	cmd := newRootCommand()
-	cmd.SetArgs(rewriteSingleDashArgs(os.Args[1:]))
+	return executeCommand(newCommand(os.Args[1:], os.LookupEnv))
+}
 
-	return executeCommand(cmd)
+// newCommand returns the command that handles args. The config
+// subcommands are only recognized by name, so a directory called config
+// can still be given as a target.
+func newCommand(args []string, lookup config.LookupFunc) *cobra.Command {
+	if isConfigCommand(args) {
+		cmd := newConfigCommand(lookup)
+		cmd.SetArgs(rewriteSingleDashArgs(args[indexSecond:]))
+
+		return cmd
+	}
+
+	cmd := newRootCommand(lookup)
+	cmd.SetArgs(rewriteSingleDashArgs(args))
+
+	return cmd
 }
 
-func newRootCommand() *cobra.Command {
-	opts := defaultFmtOptions()
+func newRootCommand(lookup config.LookupFunc) *cobra.Command {
+	// Environment variables replace the flag defaults, so flags given on
+	// the command line still take precedence.
+	opts, envErr := envFmtOptions(defaultFmtOptions(), lookup)
+	rules := defaultRuleFlags()
 
 	cmd := new(cobra.Command)
 	cmd.Use = "terraformat [options] [target...]"
@@ -170,7 +230,13 @@ func newRootCommand() *cobra.Command {
 	cmd.SilenceUsage = true
 	cmd.SilenceErrors = true
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := checkInputs(cmd, envErr, rules)
+		if err != nil {
+			return err
+		}
+
 		opts.targets = args
+		opts.overrides = append(opts.overrides, changedRules(cmd, rules))
 		ioCfg := ioConfig{
 			in:  cmd.InOrStdin(),
 			out: cmd.OutOrStdout(),
@@ -193,12 +259,14 @@ func newRootCommand() *cobra.Command {
 		return nil
 	})
 
-	cmd.Flags().BoolVar(&opts.list, flagList, true, flagList)
-	cmd.Flags().BoolVar(&opts.write, flagWrite, true, flagWrite)
-	cmd.Flags().BoolVar(&opts.diff, flagDiff, false, flagDiff)
-	cmd.Flags().BoolVar(&opts.check, flagCheck, false, flagCheck)
-	cmd.Flags().BoolVar(&opts.noColor, flagNoColor, false, flagNoColor)
-	cmd.Flags().BoolVar(&opts.recursive, flagRecursive, false, flagRecursive)
+	registerFmtFlags(cmd, &opts)
+	registerRuleFlags(cmd, &rules)
+	setFlagErrorFunc(cmd)
+
+	return cmd
+}
+
+func setFlagErrorFunc(cmd *cobra.Command) {
 	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
 		_, _ = fmt.Fprintf(
 			cmd.ErrOrStderr(),
@@ -209,8 +277,120 @@ func newRootCommand() *cobra.Command {
 
 		return exitCodeError{code: exitFlagError}
 	})
+}
 
-	return cmd
+// checkInputs reports invalid environment variables and rule flags.
+func checkInputs(cmd *cobra.Command, envErr error, rules ruleFlags) error {
+	if envErr != nil {
+		_, _ = fmt.Fprintf(
+			cmd.ErrOrStderr(),
+			"Error parsing environment variables: %s\n",
+			envErr.Error(),
+		)
+
+		return exitCodeError{code: exitFlagError}
+	}
+
+	if !slices.Contains(config.StyleNames(), rules.style) {
+		return cmd.FlagErrorFunc()(cmd, invalidStyleError(rules.style))
+	}
+
+	return nil
+}
+
+func defaultRuleFlags() ruleFlags {
+	defaults := config.Default()
+
+	return ruleFlags{
+		style:           defaults.Style,
+		blockOrder:      defaults.EnforceBlockOrder,
+		attributeOrder:  defaults.EnforceAttributeOrder,
+		topLevelSpacing: defaults.EnforceTopLevelSpacing,
+		eofNewline:      defaults.EnsureEOFNewline,
+	}
+}
+
+func registerFmtFlags(cmd *cobra.Command, opts *fmtOptions) {
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.list, flagList, opts.list, flagList)
+	flags.BoolVar(&opts.write, flagWrite, opts.write, flagWrite)
+	flags.BoolVar(&opts.diff, flagDiff, opts.diff, flagDiff)
+	flags.BoolVar(&opts.check, flagCheck, opts.check, flagCheck)
+	flags.BoolVar(&opts.noColor, flagNoColor, opts.noColor, flagNoColor)
+	flags.BoolVar(&opts.recursive, flagRecursive, opts.recursive, flagRecursive)
+	flags.StringVar(&opts.configPath, flagConfig, opts.configPath, flagConfig)
+}
+
+func registerRuleFlags(cmd *cobra.Command, rules *ruleFlags) {
+	flags := cmd.Flags()
+	flags.StringVar(&rules.style, flagStyle, rules.style, flagStyle)
+	flags.BoolVar(
+		&rules.blockOrder,
+		flagBlockOrder,
+		rules.blockOrder,
+		flagBlockOrder,
+	)
+	flags.BoolVar(
+		&rules.attributeOrder,
+		flagAttributeOrder,
+		rules.attributeOrder,
+		flagAttributeOrder,
+	)
+	flags.BoolVar(
+		&rules.topLevelSpacing,
+		flagTopLevelSpacing,
+		rules.topLevelSpacing,
+		flagTopLevelSpacing,
+	)
+	flags.BoolVar(
+		&rules.eofNewline,
+		flagEOFNewline,
+		rules.eofNewline,
+		flagEOFNewline,
+	)
+}
+
+// changedRules returns settings for the rule flags given on the command
+// line, so unset flags don't override configuration files.
+func changedRules(cmd *cobra.Command, rules ruleFlags) config.Settings {
+	var settings config.Settings
+
+	settings.Source = config.SourceFlags
+
+	if cmd.Flags().Changed(flagStyle) {
+		settings.Style = &rules.style
+	}
+
+	settings.EnforceBlockOrder = changedBool(
+		cmd,
+		flagBlockOrder,
+		rules.blockOrder,
+	)
+	settings.EnforceAttributeOrder = changedBool(
+		cmd,
+		flagAttributeOrder,
+		rules.attributeOrder,
+	)
+	settings.EnforceTopLevelSpacing = changedBool(
+		cmd,
+		flagTopLevelSpacing,
+		rules.topLevelSpacing,
+	)
+	settings.EnsureEOFNewline = changedBool(
+		cmd,
+		flagEOFNewline,
+		rules.eofNewline,
+	)
+
+	return settings
+}
+
+func changedBool(cmd *cobra.Command, name string, value bool) *bool {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+
+	return &value
 }
 
 func fmtHelpText() string {
@@ -247,21 +427,92 @@ Options:
 
   -recursive     Also process files in subdirectories. By default, only the
                  given directory (or current directory) is processed.
+
+  -config=path   Use this configuration file instead of the .terraformat.hcl
+                 files found from each target directory.
+
+  -style=name    Start from a preset: fmt (only what terraform fmt does),
+                 style-guide (the default), or strict (every optional rule).
+                 Other options and configuration files override the preset.
+
+  -block-order=false
+                 Don't reorder top-level blocks.
+
+  -attribute-order=false
+                 Don't reorder attributes and nested blocks.
+
+  -top-level-spacing=false
+                 Don't normalize blank lines between blocks and sections.
+
+  -eof-newline=false
+                 Don't ensure files end with a newline.
+
+  Options can also be set with TERRAFORMAT_<NAME> environment variables, such
+  as TERRAFORMAT_CHECK=true or TERRAFORMAT_ENFORCE_BLOCK_ORDER=false. Flags
+  take precedence over environment variables, which take precedence over
+  configuration files.
+
+Subcommands:
+
+  config print [path]       Show the configuration that applies to a file
+                            or directory, and where each value comes from.
+
+  config validate [path...] Check configuration files for errors.
 `
 }
 
 func defaultFmtOptions() fmtOptions {
 	return fmtOptions{
-		list:      true,
-		write:     true,
-		diff:      false,
-		check:     false,
-		noColor:   false,
-		recursive: false,
-		targets:   nil,
+		list:       true,
+		write:      true,
+		diff:       false,
+		check:      false,
+		noColor:    false,
+		recursive:  false,
+		configPath: emptyPath,
+		overrides:  nil,
+		targets:    nil,
 	}
 }
 
+// envFmtOptions applies TERRAFORMAT_* environment variables to opts.
+func envFmtOptions(
+	opts fmtOptions,
+	lookup config.LookupFunc,
+) (fmtOptions, error) {
+	resolved := opts
+	errs := []error{
+		envFlag(lookup, flagList, &resolved.list),
+		envFlag(lookup, flagWrite, &resolved.write),
+		envFlag(lookup, flagDiff, &resolved.diff),
+		envFlag(lookup, flagCheck, &resolved.check),
+		envFlag(lookup, flagNoColor, &resolved.noColor),
+		envFlag(lookup, flagRecursive, &resolved.recursive),
+	}
+
+	configPath, ok := lookup(config.EnvName(flagConfig))
+	if ok {
+		resolved.configPath = configPath
+	}
+
+	settings, err := config.SettingsFromEnv(lookup)
+	errs = append(errs, err)
+	resolved.overrides = append(resolved.overrides, settings)
+
+	return resolved, errors.Join(errs...)
+}
+
+func envFlag(lookup config.LookupFunc, name string, target *bool) error {
+	var value *bool
+
+	err := config.EnvBool(lookup, config.EnvName(name), &value)
+	if value != nil {
+		*target = *value
+	}
+
+	return wrapExternalError(err)
+}
+
 func executeCommand(cmd *cobra.Command) int {
 	err := cmd.Execute()
 	if err == nil {
@@ -291,11 +542,18 @@ func runFmt(cfg config.Config, opts fmtOptions, ioCfg ioConfig) int {
 
 	resolved, plan, runCfg := planCheck(resolved, colorlessCfg)
 
+	resolver := config.NewResolver(cfg)
+	resolver.SetOverrides(resolved.overrides...)
+
+	if resolved.configPath != emptyPath {
+		resolver.UseFile(resolved.configPath)
+	}
+
 	var err error
 	if stdin {
-		err = formatStdin(cfg, resolved, runCfg)
+		err = formatStdin(resolver, resolved, runCfg)
 	} else {
-		err = formatTargets(cfg, resolved, runCfg)
+		err = formatTargets(resolver, resolved, runCfg)
 	}
 
 	if err != nil {
@@ -310,7 +568,7 @@ func runFmt(cfg config.Config, opts fmtOptions, ioCfg ioConfig) int {
 func normalizeTargets(targets []string) (bool, []string) {
 	//nolint:revive // add-constant: len check is clear here.
 	if len(targets) == 0 {
-		return false, []string{"."}
+		return false, []string{currentDir}
 	}
 
 	//nolint:revive // add-constant: index check is clear here.
@@ -379,6 +637,12 @@ func fmtLongFlags() []string {
 		flagCheck,
 		flagNoColor,
 		flagRecursive,
+		flagConfig,
+		flagStyle,
+		flagBlockOrder,
+		flagAttributeOrder,
+		flagTopLevelSpacing,
+		flagEOFNewline,
 		flagHelp,
 	}
 }
@@ -426,7 +690,7 @@ func formatInput(cfg config.Config, src []byte, path string) ([]byte, error) {
 		return nil, err
 	}
 
-	out, err := format.Format(src, cfg)
+	out, err := format.FormatFile(src, path, cfg)
 	if err != nil {
 		return nil, wrapExternalError(err)
 	}
@@ -574,11 +838,20 @@ func finalizeCheck(plan checkPlan, out io.Writer) int {
 	return exitCheckDiff
 }
 
-func formatStdin(cfg config.Config, opts fmtOptions, ioCfg ioConfig) error {
+func formatStdin(
+	resolver *config.Resolver,
+	opts fmtOptions,
+	ioCfg ioConfig,
+) error {
 	if opts.write {
 		return errWriteWithStdin
 	}
 
+	cfg, err := resolver.ResolveDir(currentDir)
+	if err != nil {
+		return wrapExternalError(err)
+	}
+
 	input, err := readAll(ioCfg.in, emptyPath)
 	if err != nil {
 		return err
@@ -592,11 +865,15 @@ func formatStdin(cfg config.Config, opts fmtOptions, ioCfg ioConfig) error {
 	return handleFormattedOutput(emptyPath, input, output, opts, ioCfg)
 }
 
-func formatTargets(cfg config.Config, opts fmtOptions, ioCfg ioConfig) error {
+func formatTargets(
+	resolver *config.Resolver,
+	opts fmtOptions,
+	ioCfg ioConfig,
+) error {
 	var errs []error
 
 	for _, target := range opts.targets {
-		err := processTarget(target, opts, ioCfg, cfg)
+		err := processTarget(target, opts, ioCfg, resolver)
 		if err != nil {
 			errs = append(errs, err)
 		}
@@ -609,7 +886,7 @@ func processTarget(
 	target string,
 	opts fmtOptions,
 	ioCfg ioConfig,
-	cfg config.Config,
+	resolver *config.Resolver,
 ) error {
 	normPath := normalizePath(target)
 
@@ -622,22 +899,27 @@ func processTarget(
 	}
 
 	if info.IsDir() {
-		return processDir(normPath, opts, ioCfg, cfg)
+		return processDir(normPath, opts, ioCfg, resolver)
 	}
 
-	return processFilePath(normPath, opts, ioCfg, cfg)
+	return processFilePath(normPath, opts, ioCfg, resolver)
 }
 
 func processFilePath(
 	path string,
 	opts fmtOptions,
 	ioCfg ioConfig,
-	cfg config.Config,
+	resolver *config.Resolver,
 ) error {
 	if !isTerraformFile(path) {
 		return errUnsupportedFile
 	}
 
+	cfg, err := resolver.ResolveFile(path)
+	if err != nil {
+		return wrapExternalError(err)
+	}
+
 	//nolint:gosec // CLI intentionally reads user-provided paths.
 	inputFile, err := os.Open(path)
 	if err != nil {
@@ -742,7 +1024,7 @@ func processDir(
 	path string,
 	opts fmtOptions,
 	ioCfg ioConfig,
-	cfg config.Config,
+	resolver *config.Resolver,
 ) error {
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -759,7 +1041,7 @@ func processDir(
 	var errs []error
 
 	for _, entry := range entries {
-		err := processDirEntry(path, entry, opts, ioCfg, cfg)
+		err := processDirEntry(path, entry, opts, ioCfg, resolver)
 		if err != nil {
 			errs = append(errs, err)
 		}
@@ -773,7 +1055,7 @@ func processDirEntry(
 	entry os.DirEntry,
 	opts fmtOptions,
 	ioCfg ioConfig,
-	cfg config.Config,
+	resolver *config.Resolver,
 ) error {
 	name := entry.Name()
 	if shouldSkipFile(name) {
@@ -784,7 +1066,7 @@ func processDirEntry(
 
 	if entry.IsDir() {
 		if opts.recursive {
-			return processDir(subPath, opts, ioCfg, cfg)
+			return processDir(subPath, opts, ioCfg, resolver)
 		}
 
 		return nil
@@ -794,7 +1076,7 @@ func processDirEntry(
 		return nil
 	}
 
-	return processFilePath(subPath, opts, ioCfg, cfg)
+	return processFilePath(subPath, opts, ioCfg, resolver)
 }
 
 func normalizePath(path string) string {
@@ -18,6 +18,10 @@ const (
 	testInput = "resource \"aws_instance\" \"b\" {\n" +
 		"ami = \"ami-b\"\n" +
 		"}\n"
+	unorderedInput = "resource \"aws_instance\" \"b\" {\n" +
+		"ami = \"ami-b\"\n" +
+		"count = 1\n" +
+		"}\n"
 	mainTF               = "main.tf"
 	emptyString          = ""
 	exitCodeFormat       = "exit code: %d"
@@ -59,6 +63,9 @@ func TestRewriteSingleDashArgs(t *testing.T) {
 		"-unknown",
 		"-no-color",
 		"-recursive",
+		"-config=ci.hcl",
+		"-block-order=false",
+		"-attribute-order=false",
 	}
 	want := []string{
 		"--diff",
@@ -68,6 +75,9 @@ func TestRewriteSingleDashArgs(t *testing.T) {
 		"-unknown",
 		"--no-color",
 		"--recursive",
+		"--config=ci.hcl",
+		"--block-order=false",
+		"--attribute-order=false",
 	}
 
 	got := rewriteSingleDashArgs(args)
@@ -285,6 +295,341 @@ func TestRunFmtStdin(t *testing.T) {
 	}
 }
 
+// TestRunFmtProjectConfig ensures a project file changes the applied rules.
+func TestRunFmtProjectConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(dir, ".git"))
+	mustWriteFile(
+		t,
+		filepath.Join(dir, config.FileName),
+		[]byte("enforce_attribute_order = false\n"),
+	)
+
+	input := []byte(unorderedInput)
+	path := filepath.Join(dir, mainTF)
+	mustWriteFile(t, path, input)
+
+	opts := defaultFmtOptions()
+	opts.targets = []string{dir}
+
+	result := runFmtForTest(t, opts, bytes.NewBuffer(nil))
+	if result.code != exitOK {
+		t.Fatalf(exitCodeFormat, result.code)
+	}
+
+	cfg := config.Default()
+	cfg.EnforceAttributeOrder = false
+
+	want := mustFormatWith(t, input, cfg)
+
+	got := mustReadFile(t, path)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("formatted file mismatch:\nwant: %s\n got: %s", want, got)
+	}
+}
+
+// TestRunFmtProjectConfigError verifies config errors are reported.
+func TestRunFmtProjectConfigError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(dir, ".git"))
+	mustWriteFile(
+		t,
+		filepath.Join(dir, config.FileName),
+		[]byte("enforce_block_order = true\nbogus = 1\n"),
+	)
+	mustWriteFile(t, filepath.Join(dir, mainTF), []byte(testInput))
+
+	opts := defaultFmtOptions()
+	opts.targets = []string{dir}
+
+	result := runFmtForTest(t, opts, bytes.NewBuffer(nil))
+	if result.code != exitError {
+		t.Fatalf(exitCodeFormat, result.code)
+	}
+
+	if !strings.Contains(result.stderr, config.FileName+":2,") {
+		t.Fatalf("expected error position in stderr: %s", result.stderr)
+	}
+}
+
+// TestRootCommandRuleFlags ensures rule flags override project files.
+func TestRootCommandRuleFlags(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(dir, ".git"))
+	mustWriteFile(
+		t,
+		filepath.Join(dir, config.FileName),
+		[]byte("enforce_attribute_order = true\n"),
+	)
+
+	input := []byte(unorderedInput)
+	path := filepath.Join(dir, mainTF)
+	mustWriteFile(t, path, input)
+
+	stdout := executeForTest(t, []string{
+		"-attribute-order=false",
+		"-write=false",
+		"-list=false",
+		path,
+	})
+
+	cfg := config.Default()
+	cfg.EnforceAttributeOrder = false
+
+	want := mustFormatWith(t, input, cfg)
+	if stdout != string(want) {
+		t.Fatalf("stdout mismatch:\nwant: %s\n got: %s", want, stdout)
+	}
+}
+
+// TestRootCommandConfigFlag verifies -config replaces file discovery.
+func TestRootCommandConfigFlag(t *testing.T) {
+	t.Parallel()
+
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "ci.hcl")
+	mustWriteFile(
+		t,
+		configPath,
+		[]byte("enforce_attribute_order = false\n"),
+	)
+
+	dir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(dir, ".git"))
+
+	input := []byte(unorderedInput)
+	path := filepath.Join(dir, mainTF)
+	mustWriteFile(t, path, input)
+
+	stdout := executeForTest(t, []string{
+		"-config=" + configPath,
+		"-write=false",
+		"-list=false",
+		path,
+	})
+
+	cfg := config.Default()
+	cfg.EnforceAttributeOrder = false
+
+	want := mustFormatWith(t, input, cfg)
+	if stdout != string(want) {
+		t.Fatalf("stdout mismatch:\nwant: %s\n got: %s", want, stdout)
+	}
+}
+
+// TestRootCommandStyleFmt verifies the fmt preset keeps the order.
+func TestRootCommandStyleFmt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(dir, ".git"))
+
+	path := filepath.Join(dir, mainTF)
+	mustWriteFile(t, path, []byte(unorderedInput))
+
+	stdout := executeForTest(t, []string{
+		"-style=fmt",
+		"-write=false",
+		"-list=false",
+		path,
+	})
+
+	want := "resource \"aws_instance\" \"b\" {\n" +
+		"  ami   = \"ami-b\"\n" +
+		"  count = 1\n" +
+		"}\n"
+	if stdout != want {
+		t.Fatalf("stdout mismatch:\nwant: %s\n got: %s", want, stdout)
+	}
+}
+
+// TestRootCommandEnvironment checks env vars apply below explicit flags.
+func TestRootCommandEnvironment(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(dir, ".git"))
+	mustWriteFile(
+		t,
+		filepath.Join(dir, config.FileName),
+		[]byte("enforce_attribute_order = true\n"),
+	)
+
+	input := []byte(unorderedInput)
+	path := filepath.Join(dir, mainTF)
+	mustWriteFile(t, path, input)
+
+	env := map[string]string{
+		"TERRAFORMAT_WRITE":                   "false",
+		"TERRAFORMAT_LIST":                    "false",
+		"TERRAFORMAT_ENFORCE_ATTRIBUTE_ORDER": "false",
+		"TERRAFORMAT_ENFORCE_BLOCK_ORDER":     "false",
+	}
+
+	stdout := executeWithEnv(t, []string{"-block-order=true", path}, env)
+
+	cfg := config.Default()
+	cfg.EnforceAttributeOrder = false
+
+	want := mustFormatWith(t, input, cfg)
+	if stdout != string(want) {
+		t.Fatalf("stdout mismatch:\nwant: %s\n got: %s", want, stdout)
+	}
+
+	after := mustReadFile(t, path)
+	if !bytes.Equal(after, input) {
+		t.Fatal(errFileUnchanged)
+	}
+}
+
+// TestRootCommandInvalidEnvironment ensures bad env values are reported.
+func TestRootCommandInvalidEnvironment(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+
+	cmd := newRootCommand(func(name string) (string, bool) {
+		return "maybe", name == "TERRAFORMAT_CHECK"
+	})
+	cmd.SetArgs(nil)
+	cmd.SetOut(bytes.NewBuffer(nil))
+	cmd.SetErr(&stderr)
+
+	code := executeCommand(cmd)
+	if code != exitFlagError {
+		t.Fatalf(exitCodeFormat, code)
+	}
+
+	if !strings.Contains(stderr.String(), "TERRAFORMAT_CHECK") {
+		t.Fatalf("expected the variable name in stderr: %s", stderr.String())
+	}
+}
+
+// TestConfigPrintShowsSources checks the effective options are printed
+// with the layer that set each one.
+func TestConfigPrintShowsSources(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(dir, ".git"))
+
+	configPath := filepath.Join(dir, config.FileName)
+	mustWriteFile(t, configPath, []byte("enforce_block_order = false\n"))
+
+	env := map[string]string{"TERRAFORMAT_STYLE": "strict"}
+	stdout := executeWithEnv(
+		t,
+		[]string{"config", "print", "-eof-newline=false", dir},
+		env,
+	)
+
+	// Ignore the alignment padding.
+	output := strings.Join(strings.Fields(stdout), " ")
+	for _, want := range []string{
+		"style = \"strict\" # environment",
+		"enforce_block_order = false # " + configPath,
+		"ensure_eof_newline = false # command line",
+		"enforce_top_level_spacing = true # style \"strict\"",
+		"profile \"resource\" {",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+}
+
+// TestConfigValidateReportsPositions ensures every problem in nested
+// configuration files is reported with its position.
+func TestConfigValidateReportsPositions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(dir, ".git"))
+	mustMkdirAll(t, filepath.Join(dir, "modules"))
+	mustWriteFile(
+		t,
+		filepath.Join(dir, "modules", config.FileName),
+		[]byte("bogus = true\nblock_order = [\"1x\"]\n"),
+	)
+
+	var stderr bytes.Buffer
+
+	cmd := newCommand([]string{"config", "validate", dir}, noEnv)
+	cmd.SetOut(bytes.NewBuffer(nil))
+	cmd.SetErr(&stderr)
+
+	code := executeCommand(cmd)
+	if code != exitError {
+		t.Fatalf(exitCodeFormat, code)
+	}
+
+	for _, want := range []string{
+		config.FileName + ":1,1-6: Unsupported argument",
+		config.FileName + ":2,15-21: Invalid block type",
+	} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Fatalf("expected %q in stderr: %s", want, stderr.String())
+		}
+	}
+}
+
+// TestConfigDirectoryTarget keeps a directory named config formattable.
+func TestConfigDirectoryTarget(t *testing.T) {
+	t.Parallel()
+
+	if isConfigCommand([]string{"config"}) ||
+		isConfigCommand([]string{"config", "modules"}) {
+		t.Fatal("config targets should not select the config command")
+	}
+
+	if !isConfigCommand([]string{"config", "print"}) {
+		t.Fatal("config print should select the config command")
+	}
+}
+
+func noEnv(string) (string, bool) {
+	return "", false
+}
+
+func executeForTest(t *testing.T, args []string) string {
+	t.Helper()
+
+	return executeWithEnv(t, args, nil)
+}
+
+func executeWithEnv(
+	t *testing.T,
+	args []string,
+	env map[string]string,
+) string {
+	t.Helper()
+
+	var stdout bytes.Buffer
+
+	var stderr bytes.Buffer
+
+	cmd := newCommand(args, func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	})
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	code := executeCommand(cmd)
+	if code != exitOK {
+		t.Fatalf("exit code: %d, stderr: %s", code, stderr.String())
+	}
+
+	return stdout.String()
+}
+
 func verifyRecursiveFormatting(t *testing.T, testCase recursiveCase) {
 	t.Helper()
 
@@ -394,7 +739,13 @@ func mustReadFile(t *testing.T, path string) []byte {
 func mustFormat(t *testing.T, src []byte) []byte {
 	t.Helper()
 
-	out, err := format.Format(src, config.Default())
+	return mustFormatWith(t, src, config.Default())
+}
+
+func mustFormatWith(t *testing.T, src []byte, cfg config.Config) []byte {
+	t.Helper()
+
+	out, err := format.Format(src, cfg)
 	if err != nil {
 		t.Fatalf("format: %v", err)
 	}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mreimbold/terraformat/internal/config"
+)
+
+const (
+	configCommand         = "config"
+	configPrintCommand    = "print"
+	configValidateCommand = "validate"
+	helpCommand           = "help"
+)
+
+const maxPrintArgs = 1
+
+// isConfigCommand reports whether args invoke a config subcommand rather
+// than format a target named config.
+func isConfigCommand(args []string) bool {
+	if len(args) <= indexSecond || args[indexFirst] != configCommand {
+		return false
+	}
+
+	return slices.Contains(configSubcommands(), args[indexSecond])
+}
+
+func configSubcommands() []string {
+	return []string{
+		configPrintCommand,
+		configValidateCommand,
+		helpCommand,
+		shortFlagPrefix + flagHelp,
+		longFlagPrefix + flagHelp,
+	}
+}
+
+func newConfigCommand(lookup config.LookupFunc) *cobra.Command {
+	cmd := new(cobra.Command)
+	cmd.Use = "terraformat config <subcommand>"
+	cmd.Short = "Inspect terraformat configuration"
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.CompletionOptions.DisableDefaultCmd = true
+	cmd.SetHelpFunc(func(cmd *cobra.Command, _ []string) {
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), configHelpText())
+	})
+	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), configHelpText())
+
+		return nil
+	})
+	setFlagErrorFunc(cmd)
+	cmd.AddCommand(
+		newConfigPrintCommand(lookup),
+		newConfigValidateCommand(),
+	)
+
+	return cmd
+}
+
+func newConfigPrintCommand(lookup config.LookupFunc) *cobra.Command {
+	opts, envErr := envFmtOptions(defaultFmtOptions(), lookup)
+	rules := defaultRuleFlags()
+
+	cmd := new(cobra.Command)
+	cmd.Use = configPrintCommand + " [path]"
+	cmd.Args = cobra.MaximumNArgs(maxPrintArgs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := checkInputs(cmd, envErr, rules)
+		if err != nil {
+			return err
+		}
+
+		_, targets := normalizeTargets(args)
+		opts.overrides = append(opts.overrides, changedRules(cmd, rules))
+
+		err = printConfig(targets[indexFirst], opts, cmd)
+		if err != nil {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+
+			return exitCodeError{code: exitError}
+		}
+
+		return nil
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.configPath, flagConfig, opts.configPath, flagConfig)
+	registerRuleFlags(cmd, &rules)
+
+	return cmd
+}
+
+// printConfig writes the configuration that applies to target, with the
+// source of each option.
+func printConfig(target string, opts fmtOptions, cmd *cobra.Command) error {
+	path := normalizePath(target)
+
+	_, err := os.Stat(path)
+	if err != nil {
+		return pathError{message: "No file or directory at %s", path: path}
+	}
+
+	resolver := config.NewResolver(config.Default())
+	resolver.SetOverrides(opts.overrides...)
+
+	if opts.configPath != emptyPath {
+		resolver.UseFile(opts.configPath)
+	}
+
+	cfg, sources, err := resolver.TracePath(path)
+	if err != nil {
+		return wrapExternalError(err)
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "# Configuration for %s\n\n", path)
+	_, _ = out.Write(config.Render(cfg, sources))
+
+	return nil
+}
+
+func newConfigValidateCommand() *cobra.Command {
+	cmd := new(cobra.Command)
+	cmd.Use = configValidateCommand + " [path...]"
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		_, targets := normalizeTargets(args)
+
+		err := validateConfigFiles(targets)
+		if err != nil {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+
+			return exitCodeError{code: exitError}
+		}
+
+		_, _ = fmt.Fprintln(
+			cmd.OutOrStdout(),
+			"Success! The configuration is valid.",
+		)
+
+		return nil
+	}
+
+	return cmd
+}
+
+// validateConfigFiles parses the configuration files given as targets.
+// Directories are searched for every configuration file that applies to
+// them or to their subdirectories.
+func validateConfigFiles(targets []string) error {
+	var errs []error
+
+	seen := make(map[string]bool)
+
+	for _, target := range targets {
+		paths, err := configFilesFor(normalizePath(target))
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		for _, path := range paths {
+			if seen[path] {
+				continue
+			}
+
+			seen[path] = true
+
+			_, err := config.LoadFile(path)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func configFilesFor(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, pathError{message: "No file or directory at %s", path: path}
+	}
+
+	if info.IsDir() {
+		return config.FindAll(path), nil
+	}
+
+	return []string{path}, nil
+}
+
+func configHelpText() string {
+	return `Usage: terraformat config <subcommand> [options] [args]
+
+  Inspects the configuration that terraformat applies.
+
+Subcommands:
+
+  print [path]       Print the configuration that applies to a file or
+                     directory (default: the current directory) in
+                     configuration file syntax. Each option is followed by
+                     a comment naming its source: a configuration file, an
+                     override block, an environment variable, a command-line
+                     flag, a style preset, or the default.
+
+  validate [path...] Check configuration files for unknown options and
+                     invalid values. A directory is checked together with
+                     the files that apply to it from parent directories and
+                     every configuration file below it. Problems are reported
+                     with their file, line, and column.
+
+Options for print:
+
+  -config=path   Use this configuration file instead of the .terraformat.hcl
+                 files found from the path.
+
+  -style=name, -block-order, -attribute-order, -top-level-spacing,
+  -eof-newline   Apply these options as the formatter would.
+`
+}
@@ -1,20 +1,176 @@
 // Package config defines formatter configuration defaults.
 package config
 
+// BlockOrderFallback is the block order slot for unlisted block types.
+const BlockOrderFallback = "*"
+
+// Comparison selects how names and labels are compared when they are
+// sorted alphabetically.
+type Comparison string
+
+const (
+	// ComparisonBytewise compares names byte by byte.
+	ComparisonBytewise Comparison = "bytewise"
+	// ComparisonCaseInsensitive compares names ignoring letter case.
+	ComparisonCaseInsensitive Comparison = "case-insensitive"
+	// ComparisonNatural compares runs of digits as numbers, so subnet_2
+	// sorts before subnet_10.
+	ComparisonNatural Comparison = "natural"
+)
+
+// ComparisonNames returns the names of the comparison modes.
+func ComparisonNames() []string {
+	return []string{
+		string(ComparisonBytewise),
+		string(ComparisonCaseInsensitive),
+		string(ComparisonNatural),
+	}
+}
+
+// TfvarsOrder selects how the assignments of variable definitions files
+// (.tfvars) are ordered when attribute ordering is enforced.
+type TfvarsOrder string
+
+const (
+	// TfvarsOrderAlphabetical sorts assignments by name.
+	TfvarsOrderAlphabetical TfvarsOrder = "alphabetical"
+	// TfvarsOrderDeclaration orders assignments like the variable blocks
+	// of the module they set, with unknown names last in their original
+	// order. Files whose module declares no variables keep their order.
+	TfvarsOrderDeclaration TfvarsOrder = "declaration"
+)
+
+// TfvarsOrderNames returns the names of the tfvars ordering modes.
+func TfvarsOrderNames() []string {
+	return []string{
+		string(TfvarsOrderAlphabetical),
+		string(TfvarsOrderDeclaration),
+	}
+}
+
 // Config controls which formatting rules are applied.
 type Config struct {
+	// Style names the preset the configuration started from.
+	Style                  string
 	EnforceBlockOrder      bool
 	EnforceAttributeOrder  bool
 	EnforceTopLevelSpacing bool
 	EnsureEOFNewline       bool
+	// BlockOrder lists top-level block types in their required order.
+	// Types that are not listed take the BlockOrderFallback slot, or go
+	// last when the list has no fallback slot.
+	BlockOrder []string
+	// TestBlockOrder lists the top-level block types of test files
+	// (.tftest.hcl) in their required order. Run blocks keep their
+	// original order, since they are executed in that order.
+	TestBlockOrder []string
+	// MockBlockOrder lists the top-level block types of mock data files
+	// (.tfmock.hcl) in their required order.
+	MockBlockOrder []string
+	// SortLabels lists top-level block types whose blocks are sorted by
+	// their labels within their block order slot, such as resources by
+	// type and then name.
+	SortLabels []string
+	// SortObjectKeys lists attribute names whose object constructor
+	// values have their keys sorted, such as tags.
+	SortObjectKeys []string
+	// SortLists lists attribute names whose tuple constructor values are
+	// sets: their elements are sorted and exact duplicates removed.
+	SortLists []string
+	// SortComparison selects how every alphabetical sort compares names
+	// and labels.
+	SortComparison Comparison
+	// TfvarsOrder selects how .tfvars assignments are ordered.
+	TfvarsOrder TfvarsOrder
+	// TfvarsModule is the directory of the module whose variable
+	// declarations order .tfvars files, relative to each file. Empty
+	// means the directory of the file itself.
+	TfvarsModule string
+	// Profiles maps selectors to the ordering of matching block bodies.
+	// The most specific matching selector wins, and the ProfileFallback
+	// entry applies to blocks that match no selector.
+	Profiles map[string]Profile
 }
 
-// Default returns the default formatting configuration.
+// Default returns the default formatting configuration, which is the
+// StyleGuide preset.
 func Default() Config {
 	return Config{
+		Style:                  StyleGuide,
 		EnforceBlockOrder:      true,
 		EnforceAttributeOrder:  true,
 		EnforceTopLevelSpacing: true,
 		EnsureEOFNewline:       true,
+		BlockOrder:             DefaultBlockOrder(),
+		TestBlockOrder:         DefaultTestBlockOrder(),
+		MockBlockOrder:         DefaultMockBlockOrder(),
+		SortLabels:             DefaultSortLabels(),
+		SortObjectKeys:         nil,
+		SortLists:              DefaultSortLists(),
+		SortComparison:         ComparisonBytewise,
+		TfvarsOrder:            TfvarsOrderAlphabetical,
+		TfvarsModule:           "",
+		Profiles:               DefaultProfiles(),
+	}
+}
+
+// DefaultBlockOrder returns the default order of top-level block types.
+func DefaultBlockOrder() []string {
+	return []string{
+		"terraform",
+		"provider",
+		"variable",
+		"locals",
+		"data",
+		"resource",
+		"ephemeral",
+		"module",
+		"output",
+		"moved",
+		"import",
+		"removed",
+		"check",
+		"assert",
+		BlockOrderFallback,
+	}
+}
+
+// DefaultTestBlockOrder returns the default order of top-level block types
+// in test files.
+func DefaultTestBlockOrder() []string {
+	return []string{
+		"test",
+		"variables",
+		"provider",
+		"mock_provider",
+		"override_resource",
+		"override_data",
+		"override_module",
+		BlockOrderFallback,
+		"run",
+	}
+}
+
+// DefaultMockBlockOrder returns the default order of top-level block types
+// in mock data files.
+func DefaultMockBlockOrder() []string {
+	return []string{
+		"mock_resource",
+		"mock_data",
+		"override_resource",
+		"override_data",
+		"override_module",
+		BlockOrderFallback,
 	}
 }
+
+// DefaultSortLabels returns the block types sorted by label by default.
+func DefaultSortLabels() []string {
+	return []string{"variable", "output"}
+}
+
+// DefaultSortLists returns the meta-arguments whose lists are sorted by
+// default, because their order has no meaning.
+func DefaultSortLists() []string {
+	return []string{"depends_on", "ignore_changes", "replace_triggered_by"}
+}
@@ -0,0 +1,402 @@
+package config_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mreimbold/terraformat/internal/config"
+)
+
+const (
+	permDir  = 0o750
+	permFile = 0o644
+)
+
+// TestParseSettingsAppliesValues ensures declared options override defaults.
+func TestParseSettingsAppliesValues(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("enforce_attribute_order = false\n")
+
+	file, err := config.ParseFile(src, config.FileName)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	cfg := file.Settings.Apply(config.Default())
+	if cfg.EnforceAttributeOrder {
+		t.Fatal("attribute ordering should be disabled")
+	}
+
+	if !cfg.EnforceBlockOrder {
+		t.Fatal("unset options should keep their defaults")
+	}
+}
+
+// TestParseSettingsReportsPosition checks errors point at the failing line.
+func TestParseSettingsReportsPosition(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("enforce_block_order = true\nunknown_rule = true\n")
+
+	_, err := config.ParseFile(src, config.FileName)
+	if err == nil {
+		t.Fatal("expected an error for an unknown option")
+	}
+
+	if !strings.Contains(err.Error(), config.FileName+":2,") {
+		t.Fatalf("error should include the line number: %v", err)
+	}
+}
+
+// TestParseFileRejectsDuplicateBlockTypes checks block_order validation.
+func TestParseFileRejectsDuplicateBlockTypes(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("block_order = [\"variable\", \"variable\"]\n")
+
+	_, err := config.ParseFile(src, config.FileName)
+	if err == nil || !strings.Contains(err.Error(), "Duplicate block type") {
+		t.Fatalf("expected a duplicate block type error, got: %v", err)
+	}
+}
+
+// TestParseFileProfileMergesDefaults ensures profiles extend the built-ins.
+func TestParseFileProfileMergesDefaults(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("profile \"module\" {\n  remaining = \"original\"\n}\n")
+
+	file, err := config.ParseFile(src, config.FileName)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	cfg := file.Settings.Apply(config.Default())
+	profile := cfg.Profiles["module"]
+
+	if profile.Remaining != config.RemainingOriginal {
+		t.Fatalf("remaining mismatch: %s", profile.Remaining)
+	}
+
+	want := config.DefaultProfiles()["module"].First
+	if !slices.Equal(profile.First, want) {
+		t.Fatalf("first mismatch:\nwant: %v\n got: %v", want, profile.First)
+	}
+
+	if config.Default().Profiles["module"].Remaining == profile.Remaining {
+		t.Fatal("defaults should not be modified")
+	}
+}
+
+// TestParseFileRejectsInvalidProfile checks profile values are validated.
+func TestParseFileRejectsInvalidProfile(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("profile \"module\" {\n  remaining = \"random\"\n}\n")
+
+	_, err := config.ParseFile(src, config.FileName)
+	if err == nil || !strings.Contains(err.Error(), config.FileName+":2,") {
+		t.Fatalf("expected an invalid value error on line 2, got: %v", err)
+	}
+
+	for _, selector := range []string{"resource.", "resource..x", "resource.a.b"} {
+		src := fmt.Appendf(nil, "profile %q {\n  first = []\n}\n", selector)
+
+		_, err := config.ParseFile(src, config.FileName)
+		if err == nil || !strings.Contains(err.Error(), "Invalid profile selector") {
+			t.Fatalf("expected %q to be rejected, got: %v", selector, err)
+		}
+	}
+}
+
+// TestMergeStyleWithOverrides checks settings override a selected preset.
+func TestMergeStyleWithOverrides(t *testing.T) {
+	t.Parallel()
+
+	root, err := config.ParseFile(
+		[]byte("enforce_block_order = true\n"),
+		config.FileName,
+	)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	nested, err := config.ParseFile(
+		[]byte("style = \"fmt\"\n"),
+		config.FileName,
+	)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	cfg := config.Merge(config.Default(), root.Settings, nested.Settings)
+	if cfg.Style != config.StyleFmt || cfg.EnforceAttributeOrder {
+		t.Fatalf("fmt preset should apply: %+v", cfg)
+	}
+
+	if !cfg.EnforceBlockOrder {
+		t.Fatal("explicit settings should override the preset")
+	}
+}
+
+// TestTraceReportsSources checks every option reports the layer that set
+// it, and that new profiles report the sources they inherit.
+func TestTraceReportsSources(t *testing.T) {
+	t.Parallel()
+
+	file, err := config.ParseFile([]byte(
+		"style = \"fmt\"\n"+
+			"profile \"resource.aws_instance\" {\n"+
+			"  first = [\"ami\"]\n"+
+			"}\n",
+	), config.FileName)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	const nestedName = "nested.hcl"
+
+	nested, err := config.ParseFile(
+		[]byte("ensure_eof_newline = true\n"),
+		nestedName,
+	)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	cfg, sources := config.Trace(
+		config.Default(),
+		file.Settings,
+		nested.Settings,
+	)
+	if !cfg.EnsureEOFNewline || cfg.EnforceBlockOrder {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	want := map[string]string{
+		"style":               config.FileName,
+		"ensure_eof_newline":  nestedName,
+		"enforce_block_order": "style \"fmt\"",
+		"profile \"resource.aws_instance\" first": config.FileName,
+		"profile \"resource.aws_instance\" last":  "style \"fmt\"",
+	}
+	for key, source := range want {
+		if sources[key] != source {
+			t.Fatalf("source of %s: want %q, got %q", key, source, sources[key])
+		}
+	}
+
+	rendered := strings.Fields(string(config.Render(cfg, sources)))
+	if !strings.Contains(
+		strings.Join(rendered, " "),
+		"ensure_eof_newline = true # "+nestedName,
+	) {
+		t.Fatalf("unexpected rendering:\n%s", rendered)
+	}
+}
+
+// TestParseFileRejectsUnknownStyle ensures style names are validated.
+func TestParseFileRejectsUnknownStyle(t *testing.T) {
+	t.Parallel()
+
+	_, err := config.ParseFile([]byte("style = \"loose\"\n"), config.FileName)
+	if err == nil || !strings.Contains(err.Error(), config.FileName+":1,") {
+		t.Fatalf("expected an invalid style error on line 1, got: %v", err)
+	}
+}
+
+// TestSettingsFromEnv verifies TERRAFORMAT_* variables are decoded.
+func TestSettingsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"TERRAFORMAT_STYLE":               "strict",
+		"TERRAFORMAT_ENSURE_EOF_NEWLINE":  "0",
+		"TERRAFORMAT_BLOCK_ORDER":         "terraform, variable,*",
+		"TERRAFORMAT_ENFORCE_BLOCK_ORDER": "true",
+		"TERRAFORMAT_SORT_LABELS":         "resource,module",
+		"TERRAFORMAT_TFVARS_ORDER":        "declaration",
+		"TERRAFORMAT_TFVARS_MODULE":       "../modules/app",
+	}
+
+	settings, err := config.SettingsFromEnv(func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	})
+	if err != nil {
+		t.Fatalf("env: %v", err)
+	}
+
+	cfg := config.Merge(config.Default(), settings)
+	if cfg.Style != config.StyleStrict || cfg.EnsureEOFNewline ||
+		cfg.TfvarsOrder != config.TfvarsOrderDeclaration ||
+		cfg.TfvarsModule != "../modules/app" {
+		t.Fatalf("env settings not applied: %+v", cfg)
+	}
+
+	want := []string{"terraform", "variable", config.BlockOrderFallback}
+	if !slices.Equal(cfg.BlockOrder, want) {
+		t.Fatalf("block order mismatch:\nwant: %v\n got: %v", want, cfg.BlockOrder)
+	}
+
+	want = []string{"resource", "module"}
+	if !slices.Equal(cfg.SortLabels, want) {
+		t.Fatalf("sort labels mismatch:\nwant: %v\n got: %v", want, cfg.SortLabels)
+	}
+}
+
+// TestSettingsFromEnvFlagNames verifies rule toggles can be set by their
+// flag names, and that a boolean TERRAFORMAT_BLOCK_ORDER is a toggle.
+func TestSettingsFromEnvFlagNames(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"TERRAFORMAT_BLOCK_ORDER":               "false",
+		"TERRAFORMAT_ATTRIBUTE_ORDER":           "false",
+		"TERRAFORMAT_TOP_LEVEL_SPACING":         "false",
+		"TERRAFORMAT_EOF_NEWLINE":               "false",
+		"TERRAFORMAT_ENFORCE_ATTRIBUTE_ORDER":   "true",
+		"TERRAFORMAT_ENFORCE_TOP_LEVEL_SPACING": "true",
+	}
+
+	settings, err := config.SettingsFromEnv(func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	})
+	if err != nil {
+		t.Fatalf("env: %v", err)
+	}
+
+	cfg := config.Merge(config.Default(), settings)
+	if cfg.EnforceBlockOrder || cfg.EnsureEOFNewline ||
+		!cfg.EnforceAttributeOrder || !cfg.EnforceTopLevelSpacing {
+		t.Fatalf("env toggles not applied: %+v", cfg)
+	}
+
+	if !slices.Equal(cfg.BlockOrder, config.Default().BlockOrder) {
+		t.Fatalf("block order changed: %v", cfg.BlockOrder)
+	}
+}
+
+// TestFindFilesWalksUp verifies discovery collects parent directories.
+func TestFindFilesWalksUp(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	mustMkdirAll(t, filepath.Join(root, ".git"))
+	nested := filepath.Join(root, "modules", "network")
+	mustMkdirAll(t, nested)
+
+	rootFile := filepath.Join(root, config.FileName)
+	mustWriteFile(t, rootFile, "enforce_block_order = false\n")
+
+	nestedFile := filepath.Join(nested, config.FileName)
+	mustWriteFile(t, nestedFile, "enforce_block_order = true\n")
+
+	want := []string{rootFile, nestedFile}
+
+	got := config.FindFiles(nested)
+	if !slices.Equal(got, want) {
+		t.Fatalf("find files mismatch:\nwant: %v\n got: %v", want, got)
+	}
+}
+
+// TestFindFilesStopsAtRepositoryRoot ensures discovery ignores outer files.
+func TestFindFilesStopsAtRepositoryRoot(t *testing.T) {
+	t.Parallel()
+
+	outer := t.TempDir()
+	mustWriteFile(
+		t,
+		filepath.Join(outer, config.FileName),
+		"enforce_block_order = false\n",
+	)
+
+	repo := filepath.Join(outer, "repo")
+	mustMkdirAll(t, filepath.Join(repo, ".git"))
+
+	got := config.FindFiles(repo)
+	//nolint:revive // add-constant: len check is clear here.
+	if len(got) != 0 {
+		t.Fatalf("config outside the repository should be ignored: %v", got)
+	}
+}
+
+// TestResolverMergesNestedFiles checks nested files and overrides layer.
+func TestResolverMergesNestedFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	mustMkdirAll(t, filepath.Join(root, ".git"))
+	mustWriteFile(
+		t,
+		filepath.Join(root, config.FileName),
+		"enforce_top_level_spacing = false\n"+
+			"override \"legacy/**\" {\n"+
+			"  enforce_attribute_order = false\n"+
+			"}\n",
+	)
+
+	modules := filepath.Join(root, "modules")
+	mustMkdirAll(t, modules)
+	mustWriteFile(
+		t,
+		filepath.Join(modules, config.FileName),
+		"enforce_top_level_spacing = true\n",
+	)
+
+	legacy := filepath.Join(root, "legacy", "old")
+	mustMkdirAll(t, legacy)
+
+	resolver := config.NewResolver(config.Default())
+
+	moduleCfg := mustResolve(t, resolver, filepath.Join(modules, "main.tf"))
+	if !moduleCfg.EnforceTopLevelSpacing || !moduleCfg.EnforceAttributeOrder {
+		t.Fatalf("nested file should override its parent: %+v", moduleCfg)
+	}
+
+	legacyCfg := mustResolve(t, resolver, filepath.Join(legacy, "main.tf"))
+	if legacyCfg.EnforceTopLevelSpacing || legacyCfg.EnforceAttributeOrder {
+		t.Fatalf("override should apply below legacy/: %+v", legacyCfg)
+	}
+}
+
+func mustResolve(
+	t *testing.T,
+	resolver *config.Resolver,
+	path string,
+) config.Config {
+	t.Helper()
+
+	cfg, err := resolver.ResolveFile(path)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	return cfg
+}
+
+func mustWriteFile(t *testing.T, path string, data string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(data), permFile)
+	if err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
+
+func mustMkdirAll(t *testing.T, path string) {
+	t.Helper()
+
+	err := os.MkdirAll(path, permDir)
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// EnvPrefix prefixes the environment variables that set options. The rest
+// of each name is the upper-case option key, as in
+// TERRAFORMAT_ENFORCE_BLOCK_ORDER.
+const EnvPrefix = "TERRAFORMAT_"
+
+const envListSeparator = ","
+
+// Flag names of the rule toggles. Their variables, such as
+// TERRAFORMAT_ATTRIBUTE_ORDER, also set the toggles.
+const (
+	flagBlockOrder      = "block-order"
+	flagAttributeOrder  = "attribute-order"
+	flagTopLevelSpacing = "top-level-spacing"
+	flagEOFNewline      = "eof-newline"
+)
+
+// LookupFunc looks up an environment variable, like os.LookupEnv.
+type LookupFunc func(name string) (string, bool)
+
+type envError struct {
+	name   string
+	value  string
+	reason string
+}
+
+// Error returns the error string.
+func (err envError) Error() string {
+	return fmt.Sprintf(
+		"Invalid value %q for %s: %s",
+		err.value,
+		err.name,
+		err.reason,
+	)
+}
+
+type envBoolOption struct {
+	key    string
+	flag   string
+	target **bool
+}
+
+// EnvName returns the environment variable for an option key or flag
+// name. Dashes become underscores, as in TERRAFORMAT_NO_COLOR.
+func EnvName(key string) string {
+	name := strings.ReplaceAll(key, "-", "_")
+
+	return EnvPrefix + strings.ToUpper(name)
+}
+
+// SettingsFromEnv returns the options set by TERRAFORMAT_* variables.
+// Boolean options accept the values understood by strconv.ParseBool, list
+// options such as block_order are comma-separated lists, and the other
+// options take their configuration file value. Rule toggles can also be
+// set by their flag name, as in TERRAFORMAT_BLOCK_ORDER=false.
+func SettingsFromEnv(lookup LookupFunc) (Settings, error) {
+	settings := emptySettings()
+	settings.Source = SourceEnv
+
+	var errs []error
+
+	for _, option := range envBoolOptions(&settings) {
+		errs = append(errs, envToggle(lookup, option))
+	}
+
+	errs = append(errs, envChoice(
+		lookup,
+		attrStyle,
+		StyleNames(),
+		&settings.Style,
+	))
+	errs = append(errs, envChoice(
+		lookup,
+		attrSortComparison,
+		ComparisonNames(),
+		&settings.SortComparison,
+	))
+	errs = append(errs, envChoice(
+		lookup,
+		attrTfvarsOrder,
+		TfvarsOrderNames(),
+		&settings.TfvarsOrder,
+	))
+	envString(lookup, attrTfvarsModule, &settings.TfvarsModule)
+	// A boolean TERRAFORMAT_BLOCK_ORDER is the flag, read as a toggle.
+	if !isEnvBool(lookup, EnvName(attrBlockOrder)) {
+		errs = append(errs, envList(
+			lookup,
+			attrBlockOrder,
+			validateBlockOrder,
+			&settings.BlockOrder,
+		))
+	}
+	errs = append(errs, envList(
+		lookup,
+		attrTestBlockOrder,
+		validateBlockOrder,
+		&settings.TestBlockOrder,
+	))
+	errs = append(errs, envList(
+		lookup,
+		attrMockBlockOrder,
+		validateBlockOrder,
+		&settings.MockBlockOrder,
+	))
+	errs = append(errs, envList(
+		lookup,
+		attrSortLabels,
+		validateBlockTypes,
+		&settings.SortLabels,
+	))
+	errs = append(errs, envList(
+		lookup,
+		attrSortObjectKeys,
+		validateAttributeNames,
+		&settings.SortObjectKeys,
+	))
+	errs = append(errs, envList(
+		lookup,
+		attrSortLists,
+		validateAttributeNames,
+		&settings.SortLists,
+	))
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return emptySettings(), err
+	}
+
+	return settings, nil
+}
+
+// EnvBool parses the boolean environment variable name into target.
+// target is left unchanged when the variable is not set.
+func EnvBool(lookup LookupFunc, name string, target **bool) error {
+	raw, ok := lookup(name)
+	if !ok {
+		return nil
+	}
+
+	value, err := strconv.ParseBool(strings.TrimSpace(raw))
+	if err != nil {
+		return envError{name: name, value: raw, reason: "expected a boolean"}
+	}
+
+	*target = &value
+
+	return nil
+}
+
+// envToggle reads a rule toggle from its option key, or from its flag name
+// when the key is not set. The flag name of enforce_block_order is also
+// the name of the block_order list, so it only sets the toggle when its
+// value is a boolean.
+func envToggle(lookup LookupFunc, option envBoolOption) error {
+	name := EnvName(option.key)
+	if _, ok := lookup(name); ok {
+		return EnvBool(lookup, name, option.target)
+	}
+
+	flagName := EnvName(option.flag)
+	if option.key == attrEnforceBlockOrder && !isEnvBool(lookup, flagName) {
+		return nil
+	}
+
+	return EnvBool(lookup, flagName, option.target)
+}
+
+// isEnvBool reports whether the variable name is set to a boolean.
+func isEnvBool(lookup LookupFunc, name string) bool {
+	raw, ok := lookup(name)
+	if !ok {
+		return false
+	}
+
+	_, err := strconv.ParseBool(strings.TrimSpace(raw))
+
+	return err == nil
+}
+
+func envBoolOptions(settings *Settings) []envBoolOption {
+	return []envBoolOption{
+		{
+			key:    attrEnforceBlockOrder,
+			flag:   flagBlockOrder,
+			target: &settings.EnforceBlockOrder,
+		},
+		{
+			key:    attrEnforceAttributeOrder,
+			flag:   flagAttributeOrder,
+			target: &settings.EnforceAttributeOrder,
+		},
+		{
+			key:    attrEnforceTopLevelSpacing,
+			flag:   flagTopLevelSpacing,
+			target: &settings.EnforceTopLevelSpacing,
+		},
+		{
+			key:    attrEnsureEOFNewline,
+			flag:   flagEOFNewline,
+			target: &settings.EnsureEOFNewline,
+		},
+	}
+}
+
+// envChoice parses the option key into target, which must be one of
+// choices.
+func envChoice(
+	lookup LookupFunc,
+	key string,
+	choices []string,
+	target **string,
+) error {
+	name := EnvName(key)
+
+	raw, ok := lookup(name)
+	if !ok {
+		return nil
+	}
+
+	value := strings.TrimSpace(raw)
+	if !slices.Contains(choices, value) {
+		return envError{
+			name:   name,
+			value:  value,
+			reason: "use one of " + strings.Join(choices, ", "),
+		}
+	}
+
+	*target = &value
+
+	return nil
+}
+
+// envString reads the option key into target.
+func envString(lookup LookupFunc, key string, target **string) {
+	value, ok := lookup(EnvName(key))
+	if ok {
+		*target = &value
+	}
+}
+
+// envList parses the comma-separated list option key into target.
+func envList(
+	lookup LookupFunc,
+	key string,
+	validate func([]string, *hcl.Range) hcl.Diagnostics,
+	target *[]string,
+) error {
+	name := EnvName(key)
+
+	raw, ok := lookup(name)
+	if !ok {
+		return nil
+	}
+
+	values := splitEnvList(raw)
+
+	diags := validate(values, nil)
+	if diags.HasErrors() {
+		return envError{name: name, value: raw, reason: diags.Error()}
+	}
+
+	*target = values
+
+	return nil
+}
+
+func splitEnvList(raw string) []string {
+	parts := strings.Split(raw, envListSeparator)
+	values := make([]string, segmentFirst, len(parts))
+
+	for _, part := range parts {
+		value := strings.TrimSpace(part)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
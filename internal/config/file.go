@@ -0,0 +1,262 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// FileName is the name of the project configuration file.
+const FileName = ".terraformat.hcl"
+
+const repoMarker = ".git"
+
+type staticError string
+
+// Error returns the error string.
+func (err staticError) Error() string {
+	return string(err)
+}
+
+type pathError struct {
+	message string
+	path    string
+}
+
+// Error returns the error string.
+func (err pathError) Error() string {
+	return fmt.Sprintf(err.message, err.path)
+}
+
+const errParseFile staticError = "parse config"
+
+// File is a parsed project configuration file.
+type File struct {
+	// Dir is the directory override patterns are relative to.
+	Dir       string
+	Settings  Settings
+	overrides []override
+}
+
+type override struct {
+	pattern  string
+	settings Settings
+}
+
+type fileSchema struct {
+	Overrides []overrideSchema `hcl:"override,block"`
+	Remain    hcl.Body         `hcl:",remain"`
+}
+
+type overrideSchema struct {
+	Pattern string   `hcl:"pattern,label"`
+	Remain  hcl.Body `hcl:",remain"`
+}
+
+// Layers returns the file settings followed by every override matching
+// path, in declaration order.
+func (file File) Layers(path string) []Settings {
+	layers := []Settings{file.Settings}
+
+	rel, err := filepath.Rel(file.Dir, path)
+	if err != nil {
+		return layers
+	}
+
+	for _, entry := range file.overrides {
+		if matchPattern(entry.pattern, filepath.ToSlash(rel)) {
+			layers = append(layers, entry.settings)
+		}
+	}
+
+	return layers
+}
+
+// LoadFile parses the configuration file at path.
+func LoadFile(path string) (File, error) {
+	//nolint:gosec // configuration paths come from the user's project.
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return emptyFile(), pathError{
+			message: "Failed to read config file %s",
+			path:    path,
+		}
+	}
+
+	return ParseFile(src, path)
+}
+
+// ParseFile decodes configuration source read from filename.
+// Diagnostics include the file name, line and column of each problem.
+func ParseFile(src []byte, filename string) (File, error) {
+	parsed, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return emptyFile(), diagnosticsError(diags)
+	}
+
+	var schema fileSchema
+
+	diags = gohcl.DecodeBody(parsed.Body, nil, &schema)
+
+	file := emptyFile()
+	file.Dir = absDir(filepath.Dir(filename))
+	diags = append(diags, decodeSettings(schema.Remain, &file.Settings)...)
+	file.Settings.Source = filename
+
+	for _, block := range schema.Overrides {
+		entry := override{pattern: block.Pattern, settings: emptySettings()}
+		diags = append(diags, decodeSettings(block.Remain, &entry.settings)...)
+		entry.settings.Source = fmt.Sprintf(
+			"%s override %q",
+			filename,
+			block.Pattern,
+		)
+		file.overrides = append(file.overrides, entry)
+	}
+
+	if diags.HasErrors() {
+		return emptyFile(), diagnosticsError(diags)
+	}
+
+	return file, nil
+}
+
+// FindFiles returns the configuration files that apply to dir, outermost
+// first. The search walks up the parent directories and stops at the
+// repository root, which is the first directory containing a .git entry.
+func FindFiles(dir string) []string {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	var found []string
+
+	for {
+		candidate := filepath.Join(current, FileName)
+		if isRegularFile(candidate) {
+			found = append(found, candidate)
+		}
+
+		parent := filepath.Dir(current)
+		if exists(filepath.Join(current, repoMarker)) || parent == current {
+			break
+		}
+
+		current = parent
+	}
+
+	slices.Reverse(found)
+
+	return found
+}
+
+// FindAll returns the configuration files that apply to dir or to any
+// directory below it: the files returned by FindFiles followed by the
+// nested files in lexical order. Hidden directories are skipped.
+func FindAll(dir string) []string {
+	found := FindFiles(dir)
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return found
+	}
+
+	walk := func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			// Unreadable directories are skipped.
+			return filepath.SkipDir
+		}
+
+		if entry.IsDir() {
+			return skipHidden(path, root, entry.Name())
+		}
+
+		if entry.Name() == FileName && filepath.Dir(path) != root {
+			found = append(found, path)
+		}
+
+		return nil
+	}
+
+	_ = filepath.WalkDir(root, walk)
+
+	return found
+}
+
+func skipHidden(path string, root string, name string) error {
+	if path != root && strings.HasPrefix(name, ".") {
+		return filepath.SkipDir
+	}
+
+	return nil
+}
+
+func decodeSettings(body hcl.Body, settings *Settings) hcl.Diagnostics {
+	if body == nil {
+		return nil
+	}
+
+	// Validate whatever was decoded, so every problem is reported at once.
+	diags := gohcl.DecodeBody(body, nil, settings)
+
+	return append(diags, validateSettings(body, *settings)...)
+}
+
+// diagnosticsError reports each error diagnostic on its own line.
+func diagnosticsError(diags hcl.Diagnostics) error {
+	var errs []error
+
+	for _, diag := range diags.Errs() {
+		errs = append(errs, fmt.Errorf("%w: %w", errParseFile, diag))
+	}
+
+	return errors.Join(errs...)
+}
+
+// absDir returns dir as an absolute path so override patterns match
+// regardless of the working directory.
+func absDir(dir string) string {
+	absPath, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+
+	return absPath
+}
+
+func emptyFile() File {
+	return File{
+		Dir:       "",
+		Settings:  emptySettings(),
+		overrides: nil,
+	}
+}
+
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.Mode().IsRegular()
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
+
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w", err)
+}
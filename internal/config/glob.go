@@ -0,0 +1,65 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+const (
+	globSeparator = "/"
+	globAnyDepth  = "**"
+)
+
+const (
+	segmentFirst = 0
+	segmentRest  = 1
+)
+
+// matchPattern reports whether the slash-separated relative path matches
+// pattern. A "**" segment matches any number of directories, and a
+// pattern matching a parent directory matches every file below it.
+func matchPattern(pattern string, rel string) bool {
+	trimmed := strings.Trim(pattern, globSeparator)
+	patternParts := strings.Split(trimmed, globSeparator)
+	pathParts := strings.Split(rel, globSeparator)
+
+	for end := len(pathParts); end > segmentFirst; end-- {
+		if matchSegments(patternParts, pathParts[:end]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchSegments(patternParts []string, pathParts []string) bool {
+	if len(patternParts) == segmentFirst {
+		return len(pathParts) == segmentFirst
+	}
+
+	head := patternParts[segmentFirst]
+	if head == globAnyDepth {
+		return matchAnyDepth(patternParts[segmentRest:], pathParts)
+	}
+
+	if len(pathParts) == segmentFirst {
+		return false
+	}
+
+	matched, err := path.Match(head, pathParts[segmentFirst])
+	if err != nil || !matched {
+		return false
+	}
+
+	return matchSegments(patternParts[segmentRest:], pathParts[segmentRest:])
+}
+
+func matchAnyDepth(patternParts []string, pathParts []string) bool {
+	for skip := segmentFirst; skip <= len(pathParts); skip++ {
+		if matchSegments(patternParts, pathParts[skip:]) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package config
+
+const (
+	// StyleFmt only applies what terraform fmt does.
+	StyleFmt = "fmt"
+	// StyleGuide enforces the Terraform style guide. It is the default.
+	StyleGuide = "style-guide"
+	// StyleStrict enables every optional rule.
+	StyleStrict = "strict"
+)
+
+// StyleNames returns the names of the built-in presets.
+func StyleNames() []string {
+	return []string{StyleFmt, StyleGuide, StyleStrict}
+}
+
+// Preset returns the configuration for the named style.
+func Preset(style string) (Config, bool) {
+	switch style {
+	case StyleFmt:
+		return fmtPreset(), true
+	case StyleGuide:
+		return Default(), true
+	case StyleStrict:
+		return strictPreset(), true
+	default:
+		return Default(), false
+	}
+}
+
+// Merge applies layers onto base in order. When a layer selects a style,
+// the last selected preset replaces base, and the options set by any
+// layer still override the preset.
+func Merge(base Config, layers ...Settings) Config {
+	resolved, _ := presetBase(base, layers)
+
+	for _, layer := range layers {
+		resolved = layer.Apply(resolved)
+	}
+
+	return resolved
+}
+
+// presetBase returns the preset selected by the last layer that sets a
+// known style, and that style. It returns base and an empty style when no
+// layer selects one.
+func presetBase(base Config, layers []Settings) (Config, string) {
+	resolved := base
+	style := ""
+
+	for _, layer := range layers {
+		if layer.Style == nil {
+			continue
+		}
+
+		preset, ok := Preset(*layer.Style)
+		if ok {
+			resolved = preset
+			style = *layer.Style
+		}
+	}
+
+	return resolved, style
+}
+
+func fmtPreset() Config {
+	cfg := Default()
+	cfg.Style = StyleFmt
+	cfg.EnforceBlockOrder = false
+	cfg.EnforceAttributeOrder = false
+	cfg.EnforceTopLevelSpacing = false
+	cfg.EnsureEOFNewline = false
+
+	return cfg
+}
+
+func strictPreset() Config {
+	cfg := Default()
+	cfg.Style = StyleStrict
+	cfg.SortLabels = append(
+		cfg.SortLabels,
+		"resource",
+		"data",
+		"ephemeral",
+		"module",
+	)
+	cfg.SortObjectKeys = []string{"tags", "labels"}
+
+	locals := cfg.Profiles["locals"]
+	locals.Remaining = RemainingAlphabetical
+	cfg.Profiles["locals"] = locals
+
+	return cfg
+}
@@ -0,0 +1,384 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Remaining selects how names that are not pinned are ordered.
+type Remaining string
+
+const (
+	// RemainingAlphabetical sorts unpinned names alphabetically.
+	RemainingAlphabetical Remaining = "alphabetical"
+	// RemainingOriginal keeps unpinned names in their original order.
+	RemainingOriginal Remaining = "original"
+	// RemainingTopological places each local before the locals that
+	// reference it. Names without a dependency between them are ordered
+	// by the profile's Ties. A body with a reference cycle, or with a
+	// reference to a local it doesn't define, keeps its original order.
+	RemainingTopological Remaining = "topological"
+	// RemainingDeclaration orders the inputs of a module call like the
+	// variable blocks of the module, which is read from disk when its
+	// source is a local path. Undeclared names follow alphabetically, and
+	// calls to other modules are ordered alphabetically.
+	RemainingDeclaration Remaining = "declaration"
+)
+
+// BlockGrouping selects where nested blocks are placed in a body.
+type BlockGrouping string
+
+const (
+	// BlocksSeparate places nested blocks in their own group after the
+	// attributes.
+	BlocksSeparate BlockGrouping = "separate"
+	// BlocksMixed orders nested blocks together with the attributes.
+	BlocksMixed BlockGrouping = "mixed"
+)
+
+// ProfileFallback is the profile key used for unlisted block types.
+const ProfileFallback = "*"
+
+const (
+	// SelectorNesting joins the segments of a profile selector that
+	// targets nested blocks, as in "resource.aws_instance/ebs_block_device".
+	SelectorNesting = "/"
+	// SelectorLabel separates a block type from its first label in a
+	// selector segment, as in "resource.aws_instance".
+	SelectorLabel = "."
+)
+
+// Profile describes how the attributes and nested blocks of a block body
+// are ordered. Groups are separated by a blank line.
+type Profile struct {
+	// First lists names pinned to the start of their section, in order.
+	First []string
+	// Last lists names pinned to the end of the body, in order. Each name
+	// forms its own group.
+	Last []string
+	// GroupFirst places pinned-first attributes in their own group.
+	GroupFirst bool
+	// Blocks selects where nested blocks are placed.
+	Blocks BlockGrouping
+	// Remaining selects how unpinned names are ordered.
+	Remaining Remaining
+	// Ties orders names that RemainingTopological leaves unordered:
+	// RemainingAlphabetical or RemainingOriginal.
+	Ties Remaining
+}
+
+// DefaultProfiles returns the built-in ordering profiles keyed by
+// selector. The ProfileFallback entry applies to every other block type.
+func DefaultProfiles() map[string]Profile {
+	return map[string]Profile{
+		"resource":  resourceProfile(),
+		"data":      resourceProfile(),
+		"ephemeral": resourceProfile(),
+		"variable":  variableProfile(),
+		"output":    outputProfile(),
+		"module":    moduleProfile(),
+		"provider":  providerProfile(),
+		"terraform": terraformProfile(),
+		// Provider requirements are listed alphabetically.
+		"terraform/required_providers": newProfile(
+			nil,
+			nil,
+			RemainingAlphabetical,
+		),
+		"locals":    newProfile(nil, nil, RemainingOriginal),
+		"lifecycle": lifecycleProfile(),
+		"dynamic":   dynamicProfile(),
+		"moved":     newProfile([]string{"from", "to"}, nil, RemainingOriginal),
+		"import":    importProfile(),
+		"removed":   removedProfile(),
+		// Scoped data sources come before the assertions that use them.
+		"check":         newProfile([]string{"data"}, nil, RemainingOriginal),
+		"assert":        conditionProfile(),
+		"validation":    conditionProfile(),
+		"precondition":  conditionProfile(),
+		"postcondition": conditionProfile(),
+		"run":           runProfile(),
+		"provisioner":   provisionerProfile(),
+		// Mocks and overrides of test files and mock data files.
+		"mock_resource":     mockProfile(),
+		"mock_data":         mockProfile(),
+		"override_resource": overrideProfile(),
+		"override_data":     overrideProfile(),
+		"override_module":   overrideProfile(),
+
+		ProfileFallback: newProfile(nil, nil, RemainingOriginal),
+	}
+}
+
+func resourceProfile() Profile {
+	profile := newProfile(
+		[]string{"count", "for_each", "provider"},
+		[]string{"lifecycle", "depends_on"},
+		RemainingOriginal,
+	)
+	profile.GroupFirst = true
+
+	return profile
+}
+
+func variableProfile() Profile {
+	return newProfile(
+		[]string{
+			"type",
+			"description",
+			"default",
+			"const",
+			"sensitive",
+			"ephemeral",
+			"nullable",
+			"validation",
+		},
+		nil,
+		RemainingAlphabetical,
+	)
+}
+
+func outputProfile() Profile {
+	return newProfile(
+		[]string{"description", "value", "sensitive", "ephemeral"},
+		[]string{"depends_on"},
+		RemainingAlphabetical,
+	)
+}
+
+func moduleProfile() Profile {
+	return newProfile(
+		[]string{"source", "version", "providers", "count", "for_each"},
+		[]string{"depends_on"},
+		RemainingAlphabetical,
+	)
+}
+
+func providerProfile() Profile {
+	return newProfile(
+		[]string{"alias", "for_each"},
+		nil,
+		RemainingAlphabetical,
+	)
+}
+
+func terraformProfile() Profile {
+	return newProfile(
+		[]string{
+			"required_version",
+			"required_providers",
+			"backend",
+			"cloud",
+			// OpenTofu state and plan encryption.
+			"encryption",
+		},
+		nil,
+		RemainingAlphabetical,
+	)
+}
+
+// lifecycleProfile places preconditions before postconditions. Each keeps
+// its original order among the others.
+func lifecycleProfile() Profile {
+	return newProfile(
+		[]string{
+			"create_before_destroy",
+			"prevent_destroy",
+			"ignore_changes",
+			"replace_triggered_by",
+			"precondition",
+			"postcondition",
+		},
+		nil,
+		RemainingAlphabetical,
+	)
+}
+
+// provisionerProfile places the meta-arguments of a provisioner before
+// its arguments.
+func provisionerProfile() Profile {
+	return newProfile(
+		[]string{"when", "on_failure"},
+		nil,
+		RemainingOriginal,
+	)
+}
+
+// conditionProfile orders custom condition blocks.
+func conditionProfile() Profile {
+	return newProfile(
+		[]string{"condition", "error_message"},
+		nil,
+		RemainingOriginal,
+	)
+}
+
+func importProfile() Profile {
+	return newProfile(
+		[]string{"to", "id", "provider", "for_each", "identity"},
+		nil,
+		RemainingOriginal,
+	)
+}
+
+// removedProfile places the lifecycle block before provisioners, which
+// keep their order because it is their execution order.
+func removedProfile() Profile {
+	return newProfile(
+		[]string{"from", "lifecycle", "connection"},
+		nil,
+		RemainingOriginal,
+	)
+}
+
+// dynamicProfile orders the arguments of a dynamic block before its
+// content block. The content block itself is ordered by the profile of the
+// block it generates.
+func dynamicProfile() Profile {
+	return newProfile(
+		[]string{"for_each", "iterator", "labels"},
+		[]string{"content"},
+		RemainingOriginal,
+	)
+}
+
+// runProfile orders the run blocks of test files. Blocks and attributes
+// are mixed so the assertions follow the inputs of the run.
+func runProfile() Profile {
+	profile := newProfile(
+		[]string{
+			"command",
+			"plan_options",
+			"module",
+			"providers",
+			"variables",
+			"assert",
+			"expect_failures",
+		},
+		nil,
+		RemainingOriginal,
+	)
+	profile.Blocks = BlocksMixed
+
+	return profile
+}
+
+func mockProfile() Profile {
+	return newProfile(
+		[]string{"override_during", "defaults"},
+		nil,
+		RemainingOriginal,
+	)
+}
+
+// overrideProfile places the target of an override before the values it
+// returns: values for resources and data sources, outputs for modules.
+func overrideProfile() Profile {
+	return newProfile(
+		[]string{"target", "override_during", "values", "outputs"},
+		nil,
+		RemainingOriginal,
+	)
+}
+
+func newProfile(first []string, last []string, remaining Remaining) Profile {
+	return Profile{
+		First:      first,
+		Last:       last,
+		GroupFirst: false,
+		Blocks:     BlocksSeparate,
+		Remaining:  remaining,
+		Ties:       RemainingOriginal,
+	}
+}
+
+// profileSettings holds the profile options declared by a profile block.
+type profileSettings struct {
+	Selector   string   `hcl:"selector,label"`
+	First      []string `hcl:"first,optional"`
+	Last       []string `hcl:"last,optional"`
+	GroupFirst *bool    `hcl:"group_first,optional"`
+	Blocks     *string  `hcl:"blocks,optional"`
+	Remaining  *string  `hcl:"remaining,optional"`
+	Ties       *string  `hcl:"ties,optional"`
+	Body       hcl.Body `hcl:",body"`
+}
+
+// apply returns profile with every option set in settings applied.
+func (settings profileSettings) apply(profile Profile) Profile {
+	resolved := profile
+	if settings.First != nil {
+		resolved.First = slices.Clone(settings.First)
+	}
+
+	if settings.Last != nil {
+		resolved.Last = slices.Clone(settings.Last)
+	}
+
+	applyBool(&resolved.GroupFirst, settings.GroupFirst)
+
+	if settings.Blocks != nil {
+		resolved.Blocks = BlockGrouping(*settings.Blocks)
+	}
+
+	if settings.Remaining != nil {
+		resolved.Remaining = Remaining(*settings.Remaining)
+	}
+
+	if settings.Ties != nil {
+		resolved.Ties = Remaining(*settings.Ties)
+	}
+
+	return resolved
+}
+
+// applyProfiles merges declared profiles onto profiles. A profile for a
+// new selector starts from the profile of its innermost block type, or
+// from the fallback profile when that block type has none.
+func applyProfiles(
+	profiles map[string]Profile,
+	declared []profileSettings,
+) map[string]Profile {
+	//nolint:revive // add-constant: len check is clear here.
+	if len(declared) == 0 {
+		return profiles
+	}
+
+	resolved := maps.Clone(profiles)
+	if resolved == nil {
+		resolved = make(map[string]Profile, len(declared))
+	}
+
+	for _, settings := range declared {
+		base, ok := resolved[settings.Selector]
+		if !ok {
+			base = inheritedProfile(resolved, settings.Selector)
+		}
+
+		resolved[settings.Selector] = settings.apply(base)
+	}
+
+	return resolved
+}
+
+func inheritedProfile(profiles map[string]Profile, selector string) Profile {
+	return profiles[inheritedSelector(profiles, selector)]
+}
+
+// inheritedSelector returns the selector a new profile starts from: its
+// innermost block type when that has a profile, else the fallback.
+func inheritedSelector(profiles map[string]Profile, selector string) string {
+	segments := strings.Split(selector, SelectorNesting)
+	innermost := segments[len(segments)-segmentRest]
+	blockType, _, _ := strings.Cut(innermost, SelectorLabel)
+
+	_, ok := profiles[blockType]
+	if ok {
+		return blockType
+	}
+
+	return ProfileFallback
+}
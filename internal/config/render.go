@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+const listSeparator = ", "
+
+// Render returns cfg in configuration file syntax. Each option is followed
+// by a comment naming its source in sources, as returned by Trace.
+// Lists are written after the other options so their comments don't
+// push the others out of alignment.
+func Render(cfg Config, sources Sources) []byte {
+	var builder strings.Builder
+
+	values := optionValues(cfg)
+	writeOptions(&builder, optionScalars(), values, sources)
+	builder.WriteString("\n")
+	writeOptions(&builder, optionLists(), values, sources)
+
+	for _, selector := range slices.Sorted(maps.Keys(cfg.Profiles)) {
+		_, _ = fmt.Fprintf(&builder, "\nprofile %q {\n", selector)
+
+		values := profileValues(cfg.Profiles[selector])
+		scoped := profileSources(sources, selector)
+		writeOptions(&builder, profileScalars(), values, scoped)
+		builder.WriteString("\n")
+		writeOptions(&builder, profileLists(), values, scoped)
+		builder.WriteString("}\n")
+	}
+
+	return hclwrite.Format([]byte(builder.String()))
+}
+
+// profileSources returns the sources of the profile options for selector,
+// keyed by option name.
+func profileSources(sources Sources, selector string) Sources {
+	scoped := make(Sources)
+	for _, attr := range profileKeys() {
+		scoped[attr] = sources[profileKey(selector, attr)]
+	}
+
+	return scoped
+}
+
+func optionValues(cfg Config) map[string]string {
+	return map[string]string{
+		attrStyle:                  strconv.Quote(cfg.Style),
+		attrEnforceBlockOrder:      strconv.FormatBool(cfg.EnforceBlockOrder),
+		attrEnforceAttributeOrder:  strconv.FormatBool(cfg.EnforceAttributeOrder),
+		attrEnforceTopLevelSpacing: strconv.FormatBool(cfg.EnforceTopLevelSpacing),
+		attrEnsureEOFNewline:       strconv.FormatBool(cfg.EnsureEOFNewline),
+		attrBlockOrder:             renderList(cfg.BlockOrder),
+		attrTestBlockOrder:         renderList(cfg.TestBlockOrder),
+		attrMockBlockOrder:         renderList(cfg.MockBlockOrder),
+		attrSortLabels:             renderList(cfg.SortLabels),
+		attrSortObjectKeys:         renderList(cfg.SortObjectKeys),
+		attrSortLists:              renderList(cfg.SortLists),
+		attrSortComparison:         strconv.Quote(string(cfg.SortComparison)),
+		attrTfvarsOrder:            strconv.Quote(string(cfg.TfvarsOrder)),
+		attrTfvarsModule:           strconv.Quote(cfg.TfvarsModule),
+	}
+}
+
+func profileValues(profile Profile) map[string]string {
+	return map[string]string{
+		attrFirst:      renderList(profile.First),
+		attrLast:       renderList(profile.Last),
+		attrGroupFirst: strconv.FormatBool(profile.GroupFirst),
+		attrBlocks:     strconv.Quote(string(profile.Blocks)),
+		attrRemaining:  strconv.Quote(string(profile.Remaining)),
+		attrTies:       strconv.Quote(string(profile.Ties)),
+	}
+}
+
+func writeOptions(
+	builder *strings.Builder,
+	keys []string,
+	values map[string]string,
+	sources Sources,
+) {
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(
+			builder,
+			"%s = %s # %s\n",
+			key,
+			values[key],
+			sources[key],
+		)
+	}
+}
+
+func renderList(values []string) string {
+	quoted := make([]string, segmentFirst, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+
+	return "[" + strings.Join(quoted, listSeparator) + "]"
+}
@@ -0,0 +1,138 @@
+package config
+
+import "path/filepath"
+
+// Resolver resolves the effective configuration for formatted files.
+// Configuration files from the repository root down to the file's
+// directory are applied in order, so nested files override their parents.
+// Each configuration file is parsed once.
+type Resolver struct {
+	base      Config
+	explicit  string
+	overrides []Settings
+	files     map[string]File
+	chain     map[string][]string
+}
+
+// NewResolver returns a Resolver that applies project files onto base.
+func NewResolver(base Config) *Resolver {
+	return &Resolver{
+		base:      base,
+		explicit:  "",
+		overrides: nil,
+		files:     make(map[string]File),
+		chain:     make(map[string][]string),
+	}
+}
+
+// UseFile makes the resolver apply the configuration file at path instead
+// of discovering files from each target directory.
+func (resolver *Resolver) UseFile(path string) {
+	resolver.explicit = path
+}
+
+// SetOverrides sets options that are applied after every file, such as
+// environment variables and command-line flags, lowest precedence first.
+func (resolver *Resolver) SetOverrides(layers ...Settings) {
+	resolver.overrides = layers
+}
+
+// ResolveFile returns the configuration that applies to the file at path.
+func (resolver *Resolver) ResolveFile(path string) (Config, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return resolver.base, wrapError(err)
+	}
+
+	return resolver.resolve(filepath.Dir(absPath), absPath)
+}
+
+// ResolveDir returns the configuration that applies to input that is not
+// read from a file, such as standard input, when run from dir.
+func (resolver *Resolver) ResolveDir(dir string) (Config, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return resolver.base, wrapError(err)
+	}
+
+	return resolver.resolve(absDir, absDir)
+}
+
+// TracePath returns the configuration that applies to path, a file or a
+// directory, and the source of each option; see Trace.
+func (resolver *Resolver) TracePath(path string) (Config, Sources, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return resolver.base, nil, wrapError(err)
+	}
+
+	dir := absPath
+	if isRegularFile(absPath) {
+		dir = filepath.Dir(absPath)
+	}
+
+	layers, err := resolver.layers(dir, absPath)
+	if err != nil {
+		return resolver.base, nil, err
+	}
+
+	cfg, sources := Trace(resolver.base, layers...)
+
+	return cfg, sources, nil
+}
+
+func (resolver *Resolver) resolve(dir string, path string) (Config, error) {
+	layers, err := resolver.layers(dir, path)
+	if err != nil {
+		return resolver.base, err
+	}
+
+	return Merge(resolver.base, layers...), nil
+}
+
+// layers returns the settings that apply to path in dir, lowest
+// precedence first.
+func (resolver *Resolver) layers(dir string, path string) ([]Settings, error) {
+	var layers []Settings
+
+	for _, filePath := range resolver.filesFor(dir) {
+		file, err := resolver.load(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, file.Layers(path)...)
+	}
+
+	return append(layers, resolver.overrides...), nil
+}
+
+func (resolver *Resolver) filesFor(dir string) []string {
+	if resolver.explicit != "" {
+		return []string{resolver.explicit}
+	}
+
+	paths, ok := resolver.chain[dir]
+	if !ok {
+		paths = FindFiles(dir)
+		resolver.chain[dir] = paths
+	}
+
+	return paths
+}
+
+func (resolver *Resolver) load(path string) (File, error) {
+	file, ok := resolver.files[path]
+	if ok {
+		return file, nil
+	}
+
+	file, err := LoadFile(path)
+	if err != nil {
+		return emptyFile(), err
+	}
+
+	resolver.files[path] = file
+
+	return file, nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import "slices"
+
+// Settings holds the options declared by a configuration source.
+// Nil fields are not set by that source.
+type Settings struct {
+	// Style selects a preset; see Merge.
+	Style                  *string `hcl:"style,optional"`
+	EnforceBlockOrder      *bool   `hcl:"enforce_block_order,optional"`
+	EnforceAttributeOrder  *bool   `hcl:"enforce_attribute_order,optional"`
+	EnforceTopLevelSpacing *bool   `hcl:"enforce_top_level_spacing,optional"`
+	EnsureEOFNewline       *bool   `hcl:"ensure_eof_newline,optional"`
+	// BlockOrder is nil when block_order is not set.
+	BlockOrder     []string          `hcl:"block_order,optional"`
+	TestBlockOrder []string          `hcl:"test_block_order,optional"`
+	MockBlockOrder []string          `hcl:"mock_block_order,optional"`
+	SortLabels     []string          `hcl:"sort_labels,optional"`
+	SortObjectKeys []string          `hcl:"sort_object_keys,optional"`
+	SortLists      []string          `hcl:"sort_lists,optional"`
+	SortComparison *string           `hcl:"sort_comparison,optional"`
+	TfvarsOrder    *string           `hcl:"tfvars_order,optional"`
+	TfvarsModule   *string           `hcl:"tfvars_module,optional"`
+	Profiles       []profileSettings `hcl:"profile,block"`
+	// Source names where the settings were declared, such as the path
+	// of a configuration file. Trace reports it for each option set.
+	Source string
+}
+
+// Apply returns cfg with every option set in settings applied. The style
+// is not applied; use Merge to resolve presets.
+func (settings Settings) Apply(cfg Config) Config {
+	resolved := cfg
+	applyBool(&resolved.EnforceBlockOrder, settings.EnforceBlockOrder)
+	applyBool(&resolved.EnforceAttributeOrder, settings.EnforceAttributeOrder)
+	applyBool(&resolved.EnforceTopLevelSpacing, settings.EnforceTopLevelSpacing)
+	applyBool(&resolved.EnsureEOFNewline, settings.EnsureEOFNewline)
+
+	if settings.BlockOrder != nil {
+		resolved.BlockOrder = slices.Clone(settings.BlockOrder)
+	}
+
+	if settings.TestBlockOrder != nil {
+		resolved.TestBlockOrder = slices.Clone(settings.TestBlockOrder)
+	}
+
+	if settings.MockBlockOrder != nil {
+		resolved.MockBlockOrder = slices.Clone(settings.MockBlockOrder)
+	}
+
+	if settings.SortLabels != nil {
+		resolved.SortLabels = slices.Clone(settings.SortLabels)
+	}
+
+	if settings.SortObjectKeys != nil {
+		resolved.SortObjectKeys = slices.Clone(settings.SortObjectKeys)
+	}
+
+	if settings.SortLists != nil {
+		resolved.SortLists = slices.Clone(settings.SortLists)
+	}
+
+	if settings.SortComparison != nil {
+		resolved.SortComparison = Comparison(*settings.SortComparison)
+	}
+
+	if settings.TfvarsOrder != nil {
+		resolved.TfvarsOrder = TfvarsOrder(*settings.TfvarsOrder)
+	}
+
+	if settings.TfvarsModule != nil {
+		resolved.TfvarsModule = *settings.TfvarsModule
+	}
+
+	resolved.Profiles = applyProfiles(resolved.Profiles, settings.Profiles)
+
+	return resolved
+}
+
+func emptySettings() Settings {
+	return Settings{
+		Style:                  nil,
+		EnforceBlockOrder:      nil,
+		EnforceAttributeOrder:  nil,
+		EnforceTopLevelSpacing: nil,
+		EnsureEOFNewline:       nil,
+		BlockOrder:             nil,
+		TestBlockOrder:         nil,
+		MockBlockOrder:         nil,
+		SortLabels:             nil,
+		SortObjectKeys:         nil,
+		SortLists:              nil,
+		SortComparison:         nil,
+		TfvarsOrder:            nil,
+		TfvarsModule:           nil,
+		Profiles:               nil,
+		Source:                 "",
+	}
+}
+
+func applyBool(target *bool, value *bool) {
+	if value != nil {
+		*target = *value
+	}
+}
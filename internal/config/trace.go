@@ -0,0 +1,189 @@
+package config
+
+import "fmt"
+
+const (
+	// SourceDefault is reported for options that keep their default.
+	SourceDefault = "default"
+	// SourceEnv names the settings read from environment variables.
+	SourceEnv = "environment"
+	// SourceFlags names the settings given as command-line flags.
+	SourceFlags = "command line"
+)
+
+// Sources maps option keys to the source of their value. Keys are the
+// option names used in configuration files, such as "block_order", and
+// profile options are keyed as `profile "resource" first`.
+type Sources map[string]string
+
+// Trace resolves layers like Merge and reports where each option of the
+// result comes from. Options that no layer sets report SourceDefault, or
+// the style preset that supplied them.
+func Trace(base Config, layers ...Settings) (Config, Sources) {
+	resolved, style := presetBase(base, layers)
+
+	origin := SourceDefault
+	if style != "" {
+		origin = fmt.Sprintf("%s %q", attrStyle, style)
+	}
+
+	sources := make(Sources)
+	for _, key := range configKeys(resolved) {
+		sources[key] = origin
+	}
+
+	for _, layer := range layers {
+		inheritSources(sources, resolved.Profiles, layer.Profiles)
+		resolved = layer.Apply(resolved)
+
+		for _, key := range layer.keys() {
+			sources[key] = layer.Source
+		}
+	}
+
+	return resolved, sources
+}
+
+// inheritSources gives the options of newly declared profiles the sources
+// of the profile they start from; see applyProfiles.
+func inheritSources(
+	sources Sources,
+	profiles map[string]Profile,
+	declared []profileSettings,
+) {
+	for _, settings := range declared {
+		_, known := sources[profileKey(settings.Selector, attrFirst)]
+		if known {
+			continue
+		}
+
+		parent := inheritedSelector(profiles, settings.Selector)
+		for _, attr := range profileKeys() {
+			sources[profileKey(settings.Selector, attr)] = sources[profileKey(
+				parent,
+				attr,
+			)]
+		}
+	}
+}
+
+// keys returns the option keys set by settings.
+func (settings Settings) keys() []string {
+	set := map[string]bool{
+		attrStyle:                  settings.Style != nil,
+		attrEnforceBlockOrder:      settings.EnforceBlockOrder != nil,
+		attrEnforceAttributeOrder:  settings.EnforceAttributeOrder != nil,
+		attrEnforceTopLevelSpacing: settings.EnforceTopLevelSpacing != nil,
+		attrEnsureEOFNewline:       settings.EnsureEOFNewline != nil,
+		attrBlockOrder:             settings.BlockOrder != nil,
+		attrTestBlockOrder:         settings.TestBlockOrder != nil,
+		attrMockBlockOrder:         settings.MockBlockOrder != nil,
+		attrSortLabels:             settings.SortLabels != nil,
+		attrSortObjectKeys:         settings.SortObjectKeys != nil,
+		attrSortLists:              settings.SortLists != nil,
+		attrSortComparison:         settings.SortComparison != nil,
+		attrTfvarsOrder:            settings.TfvarsOrder != nil,
+		attrTfvarsModule:           settings.TfvarsModule != nil,
+	}
+
+	keys := setKeys(set)
+	for _, profile := range settings.Profiles {
+		keys = append(keys, profile.keys()...)
+	}
+
+	return keys
+}
+
+// keys returns the option keys set by a profile block.
+func (settings profileSettings) keys() []string {
+	set := map[string]bool{
+		attrFirst:      settings.First != nil,
+		attrLast:       settings.Last != nil,
+		attrGroupFirst: settings.GroupFirst != nil,
+		attrBlocks:     settings.Blocks != nil,
+		attrRemaining:  settings.Remaining != nil,
+		attrTies:       settings.Ties != nil,
+	}
+
+	keys := setKeys(set)
+	for index, attr := range keys {
+		keys[index] = profileKey(settings.Selector, attr)
+	}
+
+	return keys
+}
+
+func configKeys(cfg Config) []string {
+	keys := optionKeys()
+	for selector := range cfg.Profiles {
+		for _, attr := range profileKeys() {
+			keys = append(keys, profileKey(selector, attr))
+		}
+	}
+
+	return keys
+}
+
+func optionKeys() []string {
+	return append(optionScalars(), optionLists()...)
+}
+
+func profileKeys() []string {
+	return append(profileScalars(), profileLists()...)
+}
+
+// optionScalars returns the top-level options with a single value, in
+// the order they are rendered.
+func optionScalars() []string {
+	return []string{
+		attrStyle,
+		attrEnforceBlockOrder,
+		attrEnforceAttributeOrder,
+		attrEnforceTopLevelSpacing,
+		attrEnsureEOFNewline,
+		attrSortComparison,
+		attrTfvarsOrder,
+		attrTfvarsModule,
+	}
+}
+
+// optionLists returns the top-level list options in the order they are
+// rendered.
+func optionLists() []string {
+	return []string{
+		attrBlockOrder,
+		attrTestBlockOrder,
+		attrMockBlockOrder,
+		attrSortLabels,
+		attrSortObjectKeys,
+		attrSortLists,
+	}
+}
+
+// profileScalars returns the profile options with a single value, in the
+// order they are rendered.
+func profileScalars() []string {
+	return []string{attrGroupFirst, attrBlocks, attrRemaining, attrTies}
+}
+
+// profileLists returns the profile list options in the order they are
+// rendered.
+func profileLists() []string {
+	return []string{attrFirst, attrLast}
+}
+
+func profileKey(selector string, attr string) string {
+	return fmt.Sprintf("profile %q %s", selector, attr)
+}
+
+func setKeys(set map[string]bool) []string {
+	var keys []string
+
+	for key, ok := range set {
+		if ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
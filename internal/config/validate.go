@@ -0,0 +1,305 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+const (
+	attrStyle                  = "style"
+	attrEnforceBlockOrder      = "enforce_block_order"
+	attrEnforceAttributeOrder  = "enforce_attribute_order"
+	attrEnforceTopLevelSpacing = "enforce_top_level_spacing"
+	attrEnsureEOFNewline       = "ensure_eof_newline"
+	attrBlockOrder             = "block_order"
+	attrTestBlockOrder         = "test_block_order"
+	attrMockBlockOrder         = "mock_block_order"
+	attrSortLabels             = "sort_labels"
+	attrSortObjectKeys         = "sort_object_keys"
+	attrSortLists              = "sort_lists"
+	attrSortComparison         = "sort_comparison"
+	attrTfvarsOrder            = "tfvars_order"
+	attrTfvarsModule           = "tfvars_module"
+	attrFirst                  = "first"
+	attrLast                   = "last"
+	attrGroupFirst             = "group_first"
+	attrBlocks                 = "blocks"
+	attrRemaining              = "remaining"
+	attrTies                   = "ties"
+)
+
+func validateSettings(body hcl.Body, settings Settings) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if settings.BlockOrder != nil {
+		subject := attributeRange(body, attrBlockOrder)
+		diags = append(
+			diags,
+			validateBlockOrder(settings.BlockOrder, subject)...,
+		)
+	}
+
+	if settings.TestBlockOrder != nil {
+		subject := attributeRange(body, attrTestBlockOrder)
+		diags = append(
+			diags,
+			validateBlockOrder(settings.TestBlockOrder, subject)...,
+		)
+	}
+
+	if settings.MockBlockOrder != nil {
+		subject := attributeRange(body, attrMockBlockOrder)
+		diags = append(
+			diags,
+			validateBlockOrder(settings.MockBlockOrder, subject)...,
+		)
+	}
+
+	if settings.SortLabels != nil {
+		diags = append(diags, validateBlockTypes(
+			settings.SortLabels,
+			attributeRange(body, attrSortLabels),
+		)...)
+	}
+
+	diags = append(
+		diags,
+		validateNames(body, attrSortObjectKeys, settings.SortObjectKeys)...,
+	)
+	diags = append(
+		diags,
+		validateNames(body, attrSortLists, settings.SortLists)...,
+	)
+	diags = append(diags, validateChoice(
+		body,
+		attrStyle,
+		settings.Style,
+		StyleNames(),
+	)...)
+	diags = append(diags, validateChoice(
+		body,
+		attrSortComparison,
+		settings.SortComparison,
+		ComparisonNames(),
+	)...)
+	diags = append(diags, validateChoice(
+		body,
+		attrTfvarsOrder,
+		settings.TfvarsOrder,
+		TfvarsOrderNames(),
+	)...)
+
+	for _, profile := range settings.Profiles {
+		diags = append(diags, validateProfile(profile)...)
+	}
+
+	return diags
+}
+
+func validateProfile(profile profileSettings) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if !validSelector(profile.Selector) {
+		diags = append(diags, invalidValue(
+			blockRange(profile.Body),
+			"Invalid profile selector",
+			fmt.Sprintf(
+				"%q is not a valid selector; use block types with an "+
+					"optional first label, such as \"resource.aws_instance\", "+
+					"joined by \"/\" for nested blocks.",
+				profile.Selector,
+			),
+		))
+	}
+
+	diags = append(diags, validateNames(profile.Body, attrFirst, profile.First)...)
+	diags = append(diags, validateNames(profile.Body, attrLast, profile.Last)...)
+	diags = append(diags, validateChoice(
+		profile.Body,
+		attrBlocks,
+		profile.Blocks,
+		[]string{string(BlocksSeparate), string(BlocksMixed)},
+	)...)
+	diags = append(diags, validateChoice(
+		profile.Body,
+		attrRemaining,
+		profile.Remaining,
+		[]string{
+			string(RemainingAlphabetical),
+			string(RemainingOriginal),
+			string(RemainingTopological),
+			string(RemainingDeclaration),
+		},
+	)...)
+	diags = append(diags, validateChoice(
+		profile.Body,
+		attrTies,
+		profile.Ties,
+		[]string{string(RemainingAlphabetical), string(RemainingOriginal)},
+	)...)
+
+	return diags
+}
+
+func validateNames(body hcl.Body, attr string, names []string) hcl.Diagnostics {
+	return validateAttributeNames(names, attributeRange(body, attr))
+}
+
+func validateAttributeNames(
+	names []string,
+	subject *hcl.Range,
+) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, name := range names {
+		if !hclsyntax.ValidIdentifier(name) {
+			diags = append(diags, invalidValue(
+				subject,
+				"Invalid name",
+				fmt.Sprintf("%q is not a valid attribute or block name.", name),
+			))
+		}
+	}
+
+	return diags
+}
+
+func validateChoice(
+	body hcl.Body,
+	attr string,
+	value *string,
+	choices []string,
+) hcl.Diagnostics {
+	if value == nil || slices.Contains(choices, *value) {
+		return nil
+	}
+
+	return hcl.Diagnostics{invalidValue(
+		attributeRange(body, attr),
+		"Invalid value",
+		fmt.Sprintf(
+			"%q is not valid for %s; use one of %s.",
+			*value,
+			attr,
+			strings.Join(choices, ", "),
+		),
+	)}
+}
+
+func validSelector(selector string) bool {
+	if selector == ProfileFallback {
+		return true
+	}
+
+	for segment := range strings.SplitSeq(selector, SelectorNesting) {
+		blockType, label, hasLabel := strings.Cut(segment, SelectorLabel)
+		if !hclsyntax.ValidIdentifier(blockType) {
+			return false
+		}
+
+		// Selectors match the whole first label, so it can't contain
+		// another separator.
+		if hasLabel &&
+			(label == "" || strings.Contains(label, SelectorLabel)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validBlockType(name string) bool {
+	return name == BlockOrderFallback || hclsyntax.ValidIdentifier(name)
+}
+
+func validateBlockOrder(order []string, subject *hcl.Range) hcl.Diagnostics {
+	return validateTypeList(order, subject, validBlockType)
+}
+
+// validateBlockTypes checks a list of block type names, which unlike
+// block_order has no fallback slot.
+func validateBlockTypes(types []string, subject *hcl.Range) hcl.Diagnostics {
+	return validateTypeList(types, subject, hclsyntax.ValidIdentifier)
+}
+
+func validateTypeList(
+	names []string,
+	subject *hcl.Range,
+	valid func(string) bool,
+) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if !valid(name) {
+			diags = append(diags, invalidValue(
+				subject,
+				"Invalid block type",
+				fmt.Sprintf("%q is not a valid block type name.", name),
+			))
+		}
+
+		if seen[name] {
+			diags = append(diags, invalidValue(
+				subject,
+				"Duplicate block type",
+				fmt.Sprintf("%q is listed more than once.", name),
+			))
+		}
+
+		seen[name] = true
+	}
+
+	return diags
+}
+
+func invalidValue(
+	subject *hcl.Range,
+	summary string,
+	detail string,
+) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity:    hcl.DiagError,
+		Summary:     summary,
+		Detail:      detail,
+		Subject:     subject,
+		Context:     nil,
+		Expression:  nil,
+		EvalContext: nil,
+		Extra:       nil,
+	}
+}
+
+// blockRange returns the source range of the body's enclosing block.
+func blockRange(body hcl.Body) *hcl.Range {
+	syntaxBody, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	openRange := syntaxBody.SrcRange
+
+	return &openRange
+}
+
+// attributeRange returns the source range of the named attribute value.
+func attributeRange(body hcl.Body, name string) *hcl.Range {
+	syntaxBody, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	attr, ok := syntaxBody.Attributes[name]
+	if !ok {
+		return nil
+	}
+
+	valueRange := attr.Expr.Range()
+
+	return &valueRange
+}
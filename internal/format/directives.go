@@ -0,0 +1,181 @@
+package format
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+type directive int
+
+const (
+	directiveNone directive = iota
+	directiveOff
+	directiveOn
+	directiveIgnore
+	directiveKeepOrder
+)
+
+const directivePrefix = "terraformat:"
+
+// commentMarkers lists the comment delimiters stripped before matching.
+func commentMarkers() []string {
+	return []string{"#", "//", "/*", "*/"}
+}
+
+func directiveNames() map[string]directive {
+	return map[string]directive{
+		"off":        directiveOff,
+		"on":         directiveOn,
+		"ignore":     directiveIgnore,
+		"keep-order": directiveKeepOrder,
+	}
+}
+
+// applyDirectives marks items exempted by terraformat comments. An "off"
+// comment fixes every following item until an "on" comment, "ignore"
+// fixes the next item only, and "keep-order" disables reordering inside
+// the next block. The first item's comments live in leading.
+func applyDirectives(items []model.Item, leading hclwrite.Tokens) {
+	off := false
+
+	for itemIndex := range items {
+		prefix := items[itemIndex].Prefix
+		if itemIndex == model.IndexFirst {
+			prefix = leading
+		}
+
+		found := itemDirectives(prefix, items[itemIndex].Tokens)
+		off = regionState(off, found)
+
+		items[itemIndex].Fixed = off || found[directiveIgnore]
+		items[itemIndex].KeepOrder = found[directiveKeepOrder]
+	}
+}
+
+// attachRegionEnds moves an "on" comment that is not attached to the item
+// after an off region, and the comments above it, onto the last fixed
+// item, so the region ends where it was written. Only the comments
+// directly above an item move with it.
+func attachRegionEnds(items []model.Item) {
+	for itemIndex := model.IndexOffset; itemIndex < len(items); itemIndex++ {
+		prev := &items[itemIndex-model.IndexOffset]
+		if !prev.Fixed || items[itemIndex].Fixed {
+			continue
+		}
+
+		prefix := items[itemIndex].Prefix
+
+		end := regionEnd(prefix)
+		if end == model.IndexNotFound {
+			continue
+		}
+
+		prev.Tokens = append(slices.Clone(prev.Tokens), prefix[:end]...)
+		items[itemIndex].Prefix = prefix[end:]
+	}
+}
+
+// regionEnd returns the index after the line of the last "on" comment in
+// prefix, or IndexNotFound.
+func regionEnd(prefix hclwrite.Tokens) int {
+	end := model.IndexNotFound
+
+	for tokenIndex, token := range prefix {
+		if token.Type == hclsyntax.TokenComment &&
+			parseDirective(string(token.Bytes)) == directiveOn {
+			end = tokenIndex + model.IndexOffset
+		}
+	}
+
+	// Line comments include their newline; block comments are followed
+	// by one.
+	if end != model.IndexNotFound && end < len(prefix) &&
+		!bytes.HasSuffix(prefix[end-model.IndexOffset].Bytes, []byte("\n")) &&
+		prefix[end].Type == hclsyntax.TokenNewline {
+		end++
+	}
+
+	return end
+}
+
+// regionState returns whether an off region is active after found.
+func regionState(off bool, found map[directive]bool) bool {
+	if found[directiveOff] && !found[directiveOn] {
+		return true
+	}
+
+	if found[directiveOn] && !found[directiveOff] {
+		return false
+	}
+
+	return off
+}
+
+// itemDirectives collects directives from the item's prefix and from the
+// comments directly attached above it.
+func itemDirectives(
+	prefix hclwrite.Tokens,
+	itemTokens hclwrite.Tokens,
+) map[directive]bool {
+	found := make(map[directive]bool)
+
+	collect := func(token *hclwrite.Token) {
+		if token.Type != hclsyntax.TokenComment {
+			return
+		}
+
+		kind := parseDirective(string(token.Bytes))
+		if kind != directiveNone {
+			found[kind] = true
+		}
+	}
+
+	for _, token := range prefix {
+		collect(token)
+	}
+
+	for _, token := range leadingComments(itemTokens) {
+		collect(token)
+	}
+
+	return found
+}
+
+func leadingComments(itemTokens hclwrite.Tokens) hclwrite.Tokens {
+	for tokenIndex, token := range itemTokens {
+		if token.Type != hclsyntax.TokenComment &&
+			token.Type != hclsyntax.TokenNewline {
+			return itemTokens[:tokenIndex]
+		}
+	}
+
+	return itemTokens
+}
+
+// parseDirective returns the directive in a comment such as
+// "# terraformat:off". Text after the directive is ignored.
+func parseDirective(comment string) directive {
+	text := strings.TrimSpace(comment)
+	for _, marker := range commentMarkers() {
+		text = strings.TrimPrefix(text, marker)
+		text = strings.TrimSuffix(text, marker)
+	}
+
+	fields := strings.Fields(text)
+	//nolint:revive // add-constant: len check is clear here.
+	if len(fields) == 0 {
+		return directiveNone
+	}
+
+	name, ok := strings.CutPrefix(fields[model.IndexFirst], directivePrefix)
+	if !ok {
+		return directiveNone
+	}
+
+	return directiveNames()[name]
+}
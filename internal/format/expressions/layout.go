@@ -0,0 +1,309 @@
+package expressions
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+// item is one element of an object or tuple constructor: a key/value pair
+// or a list element.
+type item struct {
+	key string
+	// lead holds the comment lines directly above the item.
+	lead hclwrite.Tokens
+	// tokens runs from the start of the item through the end of its line,
+	// including a separating comma and a trailing comment.
+	tokens hclwrite.Tokens
+	// valueLen is the number of tokens before the separating comma.
+	valueLen int
+}
+
+func emptyItem() item {
+	return item{key: "", lead: nil, tokens: nil, valueLen: model.IndexFirst}
+}
+
+// itemGroup is a run of items that are not separated by blank lines.
+type itemGroup struct {
+	// separator holds the blank lines and detached comments before the
+	// group.
+	separator hclwrite.Tokens
+	items     []item
+}
+
+// itemParser reads the item starting at start and returns the index after
+// it.
+type itemParser func(inner hclwrite.Tokens, start int) (item, int, bool)
+
+// layout is a parsed object or tuple constructor.
+type layout struct {
+	open     hclwrite.Tokens
+	groups   []itemGroup
+	trailing hclwrite.Tokens
+	close    hclwrite.Tokens
+	// multiline is set when every item ends its own line.
+	multiline bool
+	// separated is set when items on separate lines still need commas
+	// between them, as in tuples.
+	separated bool
+}
+
+// parseLayout splits expr, which must be enclosed in the brackets open
+// and close, into groups of items read by parse.
+func parseLayout(
+	expr hclwrite.Tokens,
+	open hclsyntax.TokenType,
+	close hclsyntax.TokenType,
+	parse itemParser,
+) (layout, bool) {
+	parsed := layout{
+		open:      nil,
+		groups:    nil,
+		trailing:  nil,
+		close:     nil,
+		multiline: false,
+		separated: false,
+	}
+
+	last := len(expr) - model.IndexOffset
+	if last < model.IndexOffset ||
+		expr[model.IndexFirst].Type != open ||
+		expr[last].Type != close ||
+		closingIndex(expr, model.IndexFirst) != last {
+		return parsed, false
+	}
+
+	inner := expr[model.IndexOffset:last]
+	start := leadingNewlines(inner)
+	parsed.open = expr[:start+model.IndexOffset]
+	parsed.close = expr[last:]
+	parsed.multiline = start > model.IndexFirst
+
+	return parsed, parsed.parseItems(inner[start:], parse)
+}
+
+// parseItems splits the tokens between the brackets into groups of items.
+func (parsed *layout) parseItems(inner hclwrite.Tokens, parse itemParser) bool {
+	group := itemGroup{separator: nil, items: nil}
+	pending := hclwrite.Tokens{}
+	position := model.IndexFirst
+
+	for position < len(inner) {
+		token := inner[position]
+		if token.Type == hclsyntax.TokenNewline {
+			group = parsed.breakGroup(group, append(pending, token))
+			pending = hclwrite.Tokens{}
+			position++
+
+			continue
+		}
+
+		if token.Type == hclsyntax.TokenComment {
+			pending = append(pending, token)
+			position++
+
+			continue
+		}
+
+		next, end, ok := parse(inner, position)
+		if !ok || !parsed.consistentLine(next) {
+			return false
+		}
+
+		next.lead = pending
+		pending = hclwrite.Tokens{}
+		group.items = append(group.items, next)
+		position = end
+	}
+
+	parsed.groups = append(parsed.groups, group)
+	parsed.trailing = pending
+
+	return true
+}
+
+// breakGroup starts a new group at a blank line, moving the comments above
+// it into the separator.
+func (parsed *layout) breakGroup(
+	group itemGroup,
+	separator hclwrite.Tokens,
+) itemGroup {
+	//nolint:revive // add-constant: len check is clear here.
+	if len(group.items) == 0 {
+		group.separator = append(group.separator, separator...)
+
+		return group
+	}
+
+	parsed.groups = append(parsed.groups, group)
+
+	return itemGroup{separator: separator, items: nil}
+}
+
+// consistentLine reports whether next ends its line exactly when the
+// constructor is written with one item per line.
+func (parsed *layout) consistentLine(next item) bool {
+	return endsLine(next.tokens) == parsed.multiline
+}
+
+// sortGroups sorts the items of each group by key with compare.
+func (parsed layout) sortGroups(compare CompareFunc) {
+	for _, group := range parsed.groups {
+		slices.SortStableFunc(group.items, func(left, right item) int {
+			return compare(left.key, right.key)
+		})
+	}
+}
+
+// spanItem returns the item that starts at start and whose value ends at
+// valueEnd, extended over the rest of its line.
+func spanItem(
+	inner hclwrite.Tokens,
+	start int,
+	valueEnd int,
+	key string,
+) (item, int) {
+	end := lineEnd(inner, valueEnd)
+
+	return item{
+		key:      key,
+		lead:     nil,
+		tokens:   inner[start:end],
+		valueLen: valueEnd - start,
+	}, end
+}
+
+// valueEnd returns the index after the value starting at start: the first
+// newline, comma or comment outside of brackets, or the end of inner.
+func valueEnd(inner hclwrite.Tokens, start int) int {
+	position := start
+	for position < len(inner) {
+		token := inner[position]
+		switch token.Type {
+		case hclsyntax.TokenNewline,
+			hclsyntax.TokenComma,
+			hclsyntax.TokenComment:
+			return position
+		default:
+			if isOpening(token.Type) {
+				position = closingIndex(inner, position)
+			}
+		}
+
+		position++
+	}
+
+	return len(inner)
+}
+
+// lineEnd extends an item that ends at position over a separating comma
+// and the trailing comment and newline of its line.
+func lineEnd(inner hclwrite.Tokens, position int) int {
+	end := position
+	if end < len(inner) && inner[end].Type == hclsyntax.TokenComma {
+		end++
+	}
+
+	if end < len(inner) && inner[end].Type == hclsyntax.TokenComment {
+		end++
+
+		if endsLine(inner[:end]) {
+			return end
+		}
+	}
+
+	if end < len(inner) && inner[end].Type == hclsyntax.TokenNewline {
+		end++
+	}
+
+	return end
+}
+
+func (parsed layout) render() hclwrite.Tokens {
+	out := slices.Clone(parsed.open)
+
+	for groupIndex, group := range parsed.groups {
+		out = append(out, group.separator...)
+
+		for index, next := range group.items {
+			last := groupIndex == len(parsed.groups)-model.IndexOffset &&
+				index == len(group.items)-model.IndexOffset
+
+			out = append(out, next.lead...)
+			out = append(out, parsed.itemTokens(next, last)...)
+		}
+	}
+
+	out = append(out, parsed.trailing...)
+
+	return append(out, parsed.close...)
+}
+
+// itemTokens returns the tokens of next. Items of a single-line
+// constructor are separated by commas wherever they end up, and so are
+// the items of a multi-line tuple, whose last item keeps its own comma.
+func (parsed layout) itemTokens(next item, last bool) hclwrite.Tokens {
+	if parsed.multiline {
+		if !parsed.separated || last || next.hasComma() {
+			return next.tokens
+		}
+
+		out := slices.Clone(next.tokens[:next.valueLen])
+		out = append(out, commaToken())
+
+		return append(out, next.tokens[next.valueLen:]...)
+	}
+
+	itemTokens := slices.Clone(next.tokens[:next.valueLen])
+	if last {
+		return itemTokens
+	}
+
+	return append(itemTokens, commaToken())
+}
+
+// hasComma reports whether a comma follows the value of next.
+func (next item) hasComma() bool {
+	return next.valueLen < len(next.tokens) &&
+		next.tokens[next.valueLen].Type == hclsyntax.TokenComma
+}
+
+// commented reports whether next has a lead or trailing comment.
+func (next item) commented() bool {
+	return containsComment(next.lead) || containsComment(next.tokens)
+}
+
+func containsComment(itemTokens hclwrite.Tokens) bool {
+	return slices.ContainsFunc(itemTokens, func(token *hclwrite.Token) bool {
+		return token.Type == hclsyntax.TokenComment
+	})
+}
+
+func commaToken() *hclwrite.Token {
+	return &hclwrite.Token{
+		Type:         hclsyntax.TokenComma,
+		Bytes:        []byte(","),
+		SpacesBefore: model.IndexFirst,
+	}
+}
+
+// endsLine reports whether itemTokens end with a newline or with a line
+// comment, which includes its newline.
+func endsLine(itemTokens hclwrite.Tokens) bool {
+	last := itemTokens[len(itemTokens)-model.IndexOffset]
+
+	return last.Type == hclsyntax.TokenNewline ||
+		strings.HasSuffix(string(last.Bytes), "\n")
+}
+
+func leadingNewlines(inner hclwrite.Tokens) int {
+	count := model.IndexFirst
+	for count < len(inner) && inner[count].Type == hclsyntax.TokenNewline {
+		count++
+	}
+
+	return count
+}
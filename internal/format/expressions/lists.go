@@ -0,0 +1,80 @@
+package expressions
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// SortList reorders the elements of the tuple constructor in expr by their
+// source text with compare, and removes elements that repeat an earlier
+// one. Elements move like the items of SortObject, and a duplicate that
+// carries a comment is kept so the comment is not lost. It reports false
+// when expr is not a tuple constructor it can reorder, such as a for
+// expression or a list with several elements on some of its lines.
+func SortList(
+	expr hclwrite.Tokens,
+	compare CompareFunc,
+) (hclwrite.Tokens, bool) {
+	parsed, ok := parseLayout(
+		expr,
+		hclsyntax.TokenOBrack,
+		hclsyntax.TokenCBrack,
+		parseListItem,
+	)
+	if !ok {
+		return expr, false
+	}
+
+	parsed.separated = true
+	parsed.sortGroups(compare)
+	parsed.removeDuplicates()
+
+	return parsed.render(), true
+}
+
+// parseListItem reads the element starting at start and returns the index
+// after it.
+func parseListItem(inner hclwrite.Tokens, start int) (item, int, bool) {
+	first := inner[start]
+	if first.Type == hclsyntax.TokenIdent && string(first.Bytes) == forKeyword {
+		return emptyItem(), start, false
+	}
+
+	end := valueEnd(inner, start)
+	if end == start {
+		return emptyItem(), start, false
+	}
+
+	key := strings.TrimSpace(string(inner[start:end].Bytes()))
+	next, end := spanItem(inner, start, end, key)
+
+	return next, end, true
+}
+
+// removeDuplicates drops the elements whose source text repeats an earlier
+// element, unless they carry a comment.
+func (parsed *layout) removeDuplicates() {
+	seen := make(map[string]bool)
+
+	for index, group := range parsed.groups {
+		parsed.groups[index].items = slices.DeleteFunc(
+			group.items,
+			func(next item) bool {
+				duplicate := seen[next.key] && !next.commented()
+				seen[next.key] = true
+
+				return duplicate
+			},
+		)
+	}
+
+	// A run left empty is dropped with its blank line, unless comments
+	// separate it from the previous run.
+	parsed.groups = slices.DeleteFunc(parsed.groups, func(group itemGroup) bool {
+		//nolint:revive // add-constant: len check is clear here.
+		return len(group.items) == 0 && !containsComment(group.separator)
+	})
+}
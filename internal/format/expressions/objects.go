@@ -0,0 +1,108 @@
+// Package expressions reorders the elements of attribute expressions.
+package expressions
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+const forKeyword = "for"
+
+// CompareFunc compares two object keys, like strings.Compare.
+type CompareFunc func(left string, right string) int
+
+// SortObject reorders the items of the object constructor in expr by key
+// with compare. Items only move within runs that are not separated by
+// blank lines, and the comments on the lines above an item and at the end
+// of its line move with it. It reports false when expr is not an object
+// constructor it can reorder, such as a for expression, an object with a
+// computed key, or an object with several items on some of its lines.
+func SortObject(
+	expr hclwrite.Tokens,
+	compare CompareFunc,
+) (hclwrite.Tokens, bool) {
+	parsed, ok := parseLayout(
+		expr,
+		hclsyntax.TokenOBrace,
+		hclsyntax.TokenCBrace,
+		parseObjectItem,
+	)
+	if !ok {
+		return expr, false
+	}
+
+	parsed.sortGroups(compare)
+
+	return parsed.render(), true
+}
+
+// parseObjectItem reads the key/value pair starting at start and returns
+// the index after it.
+func parseObjectItem(inner hclwrite.Tokens, start int) (item, int, bool) {
+	separator := keySeparator(inner, start)
+	if separator == model.IndexNotFound {
+		return emptyItem(), start, false
+	}
+
+	key, ok := keyName(inner[start:separator])
+	if !ok {
+		return emptyItem(), start, false
+	}
+
+	end := valueEnd(inner, separator+model.IndexOffset)
+	next, end := spanItem(inner, start, end, key)
+
+	return next, end, true
+}
+
+// keySeparator returns the index of the = or : after the key at start.
+func keySeparator(inner hclwrite.Tokens, start int) int {
+	for position := start; position < len(inner); position++ {
+		switch inner[position].Type {
+		case hclsyntax.TokenEqual, hclsyntax.TokenColon:
+			return position
+		case hclsyntax.TokenNewline, hclsyntax.TokenComma:
+			return model.IndexNotFound
+		default:
+			if isOpening(inner[position].Type) {
+				return model.IndexNotFound
+			}
+		}
+	}
+
+	return model.IndexNotFound
+}
+
+// keyName returns the name of a quoted literal key, or of an identifier
+// key such as aws or a dotted one such as aws.west.
+func keyName(key hclwrite.Tokens) (string, bool) {
+	if len(key) == keyQuotedLen &&
+		key[model.IndexFirst].Type == hclsyntax.TokenOQuote &&
+		key[model.IndexOffset].Type == hclsyntax.TokenQuotedLit &&
+		key[keyQuotedLen-model.IndexOffset].Type == hclsyntax.TokenCQuote {
+		return string(key[model.IndexOffset].Bytes), true
+	}
+
+	var name strings.Builder
+
+	for index, token := range key {
+		want := hclsyntax.TokenIdent
+		if index%keyStep == keyStepDot {
+			want = hclsyntax.TokenDot
+		}
+
+		if token.Type != want {
+			return "", false
+		}
+
+		name.Write(token.Bytes)
+	}
+
+	// A dotted key ends with an identifier.
+	valid := len(key)%keyStep == keyStepDot && name.String() != forKeyword
+
+	return name.String(), valid
+}
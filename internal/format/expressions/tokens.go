@@ -0,0 +1,66 @@
+package expressions
+
+import (
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+const keyQuotedLen = 3
+
+// Dotted keys alternate identifiers, at even indexes, and dots.
+const (
+	keyStep    = 2
+	keyStepDot = 1
+)
+
+// closingIndex returns the index of the token that closes the bracket,
+// brace, parenthesis, interpolation or heredoc opened at open. It returns
+// the last index when the opening token is never closed.
+func closingIndex(exprTokens hclwrite.Tokens, open int) int {
+	depth := model.IndexFirst
+
+	for position := open; position < len(exprTokens); position++ {
+		tokenType := exprTokens[position].Type
+		if isOpening(tokenType) {
+			depth++
+		}
+
+		if isClosing(tokenType) {
+			depth--
+		}
+
+		if depth == model.IndexFirst {
+			return position
+		}
+	}
+
+	return len(exprTokens) - model.IndexOffset
+}
+
+func isOpening(tokenType hclsyntax.TokenType) bool {
+	switch tokenType {
+	case hclsyntax.TokenOBrace,
+		hclsyntax.TokenOBrack,
+		hclsyntax.TokenOParen,
+		hclsyntax.TokenTemplateInterp,
+		hclsyntax.TokenTemplateControl,
+		hclsyntax.TokenOHeredoc:
+		return true
+	default:
+		return false
+	}
+}
+
+func isClosing(tokenType hclsyntax.TokenType) bool {
+	switch tokenType {
+	case hclsyntax.TokenCBrace,
+		hclsyntax.TokenCBrack,
+		hclsyntax.TokenCParen,
+		hclsyntax.TokenTemplateSeqEnd,
+		hclsyntax.TokenCHeredoc:
+		return true
+	default:
+		return false
+	}
+}
@@ -4,12 +4,15 @@ package format
 import (
 	"bytes"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mreimbold/terraformat/internal/config"
+	"github.com/mreimbold/terraformat/internal/format/expressions"
 	"github.com/mreimbold/terraformat/internal/format/model"
 	"github.com/mreimbold/terraformat/internal/format/ordering"
 	"github.com/mreimbold/terraformat/internal/format/spacing"
@@ -23,6 +26,18 @@ func (err staticError) Error() string {
 	return string(err)
 }
 
+const (
+	blockDynamic = "dynamic"
+	blockContent = "content"
+	blockModule  = "module"
+)
+
+const (
+	extensionTest      = ".tftest.hcl"
+	extensionMock      = ".tfmock.hcl"
+	extensionVariables = ".tfvars"
+)
+
 const (
 	errParseConfig    staticError = "parse config"
 	errLocateItemSpan staticError = "locate item span"
@@ -30,6 +45,63 @@ const (
 
 // Format applies terraformat rules to a Terraform/HCL document.
 func Format(src []byte, cfg config.Config) ([]byte, error) {
+	return formatContext(src, model.RootContext(model.FileConfig), cfg)
+}
+
+// FormatFile applies terraformat rules to the document read from path.
+// The file extension selects the top-level ordering, so test files
+// (.tftest.hcl) and mock data files (.tfmock.hcl) use their own block
+// orders. Variable definitions files (.tfvars) can be ordered by the
+// variable declarations of their module, and module calls by those of
+// the called module, which are read from disk.
+func FormatFile(src []byte, path string, cfg config.Config) ([]byte, error) {
+	return formatContext(src, fileContext(path, cfg), cfg)
+}
+
+// fileContext returns the root context of the file at path.
+func fileContext(path string, cfg config.Config) model.Context {
+	ctx := model.RootContext(fileKind(path))
+	if path != model.EmptyString {
+		ctx.Dir = filepath.Dir(path)
+	}
+
+	if ctx.File == model.FileVariables &&
+		cfg.TfvarsOrder == config.TfvarsOrderDeclaration {
+		dir := filepath.Join(ctx.Dir, cfg.TfvarsModule)
+		if filepath.IsAbs(cfg.TfvarsModule) {
+			dir = cfg.TfvarsModule
+		}
+
+		ctx.Inputs = declaredVariables(dir)
+	}
+
+	return ctx
+}
+
+// fileKind returns the kind of the file at path. Paths without a known
+// extension, such as standard input, are configuration files.
+func fileKind(path string) model.FileKind {
+	name := strings.ToLower(filepath.Base(path))
+	if strings.HasSuffix(name, extensionTest) {
+		return model.FileTest
+	}
+
+	if strings.HasSuffix(name, extensionMock) {
+		return model.FileMock
+	}
+
+	if strings.HasSuffix(name, extensionVariables) {
+		return model.FileVariables
+	}
+
+	return model.FileConfig
+}
+
+func formatContext(
+	src []byte,
+	ctx model.Context,
+	cfg config.Config,
+) ([]byte, error) {
 	startPos := hcl.Pos{
 		Line:   model.StartLine,
 		Column: model.StartColumn,
@@ -41,14 +113,12 @@ func Format(src []byte, cfg config.Config) ([]byte, error) {
 		return nil, fmt.Errorf("%w: %s", errParseConfig, diags.Error())
 	}
 
-	ctx := model.Context{Root: true, BlockType: model.EmptyString}
-
 	err := rewriteBody(file.Body(), ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	out := file.Bytes()
+	out := restoreVerbatim(file.Bytes())
 	if cfg.EnsureEOFNewline {
 		out = ensureTrailingNewline(out)
 	}
@@ -61,18 +131,26 @@ func rewriteBody(
 	ctx model.Context,
 	cfg config.Config,
 ) error {
-	err := rewriteChildBlocks(body, cfg)
+	collection, err := collectBodyItems(body)
 	if err != nil {
 		return err
 	}
 
-	collection, err := collectBodyItems(body)
+	if collection.IsEmpty() {
+		return nil
+	}
+
+	err = rewriteChildBlocks(collection.Items, ctx, cfg)
 	if err != nil {
 		return err
 	}
 
-	if collection.IsEmpty() {
-		return nil
+	rewriteExpressions(body, collection.Items, ctx, cfg)
+
+	// Child rewrites replace nested tokens, so collect the items again.
+	collection, err = collectBodyItems(body)
+	if err != nil {
+		return err
 	}
 
 	if shouldApplyOrdering(cfg, ctx) {
@@ -93,14 +171,22 @@ func rewriteBody(
 	return nil
 }
 
-func rewriteChildBlocks(body *hclwrite.Body, cfg config.Config) error {
+func rewriteChildBlocks(
+	items []model.Item,
+	ctx model.Context,
+	cfg config.Config,
+) error {
 	// Rewrite nested blocks first to avoid losing structure after reordering.
-	for _, block := range body.Blocks() {
-		childCtx := model.Context{
-			Root:      false,
-			BlockType: block.Type(),
+	for _, item := range items {
+		if item.Kind != model.ItemBlock || item.Fixed {
+			continue
 		}
 
+		block := item.Block
+		childCtx := childContext(ctx, block)
+		childCtx.KeepOrder = childCtx.KeepOrder || item.KeepOrder
+		childCtx.Inputs = moduleInputs(childCtx, block, cfg)
+
 		err := rewriteBody(block.Body(), childCtx, cfg)
 		if err != nil {
 			return err
@@ -110,7 +196,95 @@ func rewriteChildBlocks(body *hclwrite.Body, cfg config.Config) error {
 	return nil
 }
 
+// rewriteExpressions reorders the keys of object constructors that have a
+// canonical order, such as required_providers entries, and the elements
+// of lists that are sets, such as depends_on.
+func rewriteExpressions(
+	body *hclwrite.Body,
+	items []model.Item,
+	ctx model.Context,
+	cfg config.Config,
+) {
+	if ctx.KeepOrder || !cfg.EnforceAttributeOrder {
+		return
+	}
+
+	for _, item := range items {
+		if item.Kind != model.ItemAttribute || item.Fixed {
+			continue
+		}
+
+		sorted, ok := sortExpression(item, ctx, cfg)
+		if ok {
+			body.SetAttributeRaw(item.Name, sorted)
+		}
+	}
+}
+
+// sortExpression returns the expression of the attribute item with its
+// object keys or list elements sorted. It reports false when the
+// expression keeps its order.
+func sortExpression(
+	item model.Item,
+	ctx model.Context,
+	cfg config.Config,
+) (hclwrite.Tokens, bool) {
+	exprTokens := item.Attr.Expr().BuildTokens(nil)
+
+	compare, ok := ordering.ObjectOrder(item.Name, ctx, cfg)
+	if ok {
+		return expressions.SortObject(exprTokens, compare)
+	}
+
+	compare, ok = ordering.ListOrder(item.Name, cfg)
+	if ok {
+		return expressions.SortList(exprTokens, compare)
+	}
+
+	return nil, false
+}
+
+// childContext returns the context of block nested in ctx. The content
+// block of a dynamic block is ordered like the block it generates, as if
+// it were written in place of the dynamic block.
+func childContext(ctx model.Context, block *hclwrite.Block) model.Context {
+	//nolint:revive // add-constant: len check is clear here.
+	if ctx.BlockType == blockDynamic &&
+		block.Type() == blockContent &&
+		len(ctx.Labels) > 0 {
+		return ctx.Sibling(ctx.Labels[model.IndexFirst], nil)
+	}
+
+	return ctx.Child(block.Type(), block.Labels())
+}
+
+// moduleInputs returns the variables declared by the module that block
+// calls when its body is ordered by declaration and its source is a local
+// path. It returns nil for other blocks and for remote sources.
+func moduleInputs(
+	ctx model.Context,
+	block *hclwrite.Block,
+	cfg config.Config,
+) []string {
+	if block.Type() != blockModule ||
+		ctx.Dir == model.EmptyString ||
+		!ordering.OrdersByDeclaration(ctx, cfg) {
+		return nil
+	}
+
+	source, ok := localSource(block)
+	if !ok {
+		return nil
+	}
+
+	return declaredVariables(filepath.Join(ctx.Dir, source))
+}
+
 func shouldApplyOrdering(cfg config.Config, ctx model.Context) bool {
+	if ctx.KeepOrder {
+		return false
+	}
+
 	if cfg.EnforceAttributeOrder {
 		return true
 	}
@@ -139,6 +313,8 @@ func collectBodyItems(body *hclwrite.Body) (model.Items, error) {
 
 	sortItemsByStart(items)
 	prefixes := applyItemPrefixes(items, bodyTokens)
+	applyDirectives(items, prefixes.Leading)
+	attachRegionEnds(items)
 
 	return model.Items{
 		Leading:  prefixes.Leading,
@@ -177,7 +353,7 @@ func collectBlockItems(blocks []*hclwrite.Block) []model.Item {
 			nil,
 			block,
 			block.Type(),
-			strings.Join(block.Labels(), "."),
+			strings.Join(block.Labels(), model.LabelSeparator),
 			block.BuildTokens(nil),
 		)
 		items = append(items, item)
@@ -240,9 +416,29 @@ func renderBody(
 	ctx model.Context,
 	cfg config.Config,
 ) hclwrite.Tokens {
-	out := spacing.NormalizeLeadingTokens(leading)
+	keepBlankLines := spacing.KeepsBlankLines(ctx, cfg)
+
+	// The prefixes share their backing array, so appending must not write
+	// into it.
+	out := slices.Clone(leading)
+	if !keepBlankLines {
+		out = slices.Clone(spacing.NormalizeLeadingTokens(leading))
+	}
 
 	for itemIndex, item := range items {
+		if item.Fixed {
+			out = append(out, verbatimToken(item.Prefix, item.Tokens))
+
+			continue
+		}
+
+		if keepBlankLines {
+			out = append(out, spacing.KeptPrefixTokens(items, itemIndex)...)
+			out = append(out, item.Tokens...)
+
+			continue
+		}
+
 		insertBlank := spacing.ShouldInsertBlankLine(items, itemIndex, ctx, cfg)
 		if insertBlank {
 			out = append(out, spacing.NewlineToken())
@@ -289,5 +485,7 @@ func newItem(
 		OrigIndex: model.IndexFirst,
 		Start:     model.IndexFirst,
 		End:       model.IndexFirst,
+		Fixed:     false,
+		KeepOrder: false,
 	}
 }
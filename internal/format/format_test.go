@@ -17,9 +17,9 @@ const replaceOnce = 1
 func TestFormatGolden(t *testing.T) {
 	t.Parallel()
 
-	inputs := mustGlob(t, "testdata/*.input.tf")
+	inputs := mustGlob(t, "testdata/*.input.*")
 	for _, inputPath := range inputs {
-		name := strings.TrimSuffix(filepath.Base(inputPath), ".input.tf")
+		name, _, _ := strings.Cut(filepath.Base(inputPath), ".input.")
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 			runGoldenCase(t, inputPath)
@@ -44,13 +44,14 @@ func runGoldenCase(t *testing.T, inputPath string) {
 	t.Helper()
 
 	src := mustReadFile(t, inputPath)
-	got := mustFormat(t, src)
-	golden := strings.Replace(
-		inputPath,
-		".input.tf",
-		".golden.tf",
-		replaceOnce,
-	)
+
+	// The input's extension selects the file kind, as for real files.
+	got, err := tfmt.FormatFile(src, inputPath, goldenConfig(t, inputPath))
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+
+	golden := strings.Replace(inputPath, ".input.", ".golden.", replaceOnce)
 	want := mustReadFile(t, golden)
 
 	if !bytes.Equal(got, want) {
@@ -60,10 +61,36 @@ func runGoldenCase(t *testing.T, inputPath string) {
 	}
 }
 
+// goldenConfig applies the optional <name>.config.hcl next to an input.
+func goldenConfig(t *testing.T, inputPath string) config.Config {
+	t.Helper()
+
+	name, _, _ := strings.Cut(inputPath, ".input.")
+	path := name + ".config.hcl"
+
+	_, err := os.Stat(path)
+	if err != nil {
+		return config.Default()
+	}
+
+	file, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("load %s: %v", path, err)
+	}
+
+	return config.Merge(config.Default(), file.Settings)
+}
+
 func mustFormat(t *testing.T, src []byte) []byte {
 	t.Helper()
 
-	formatted, err := tfmt.Format(src, config.Default())
+	return mustFormatWith(t, src, config.Default())
+}
+
+func mustFormatWith(t *testing.T, src []byte, cfg config.Config) []byte {
+	t.Helper()
+
+	formatted, err := tfmt.Format(src, cfg)
 	if err != nil {
 		t.Fatalf("format: %v", err)
 	}
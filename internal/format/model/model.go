@@ -1,7 +1,11 @@
 // Package model defines shared formatting data structures.
 package model
 
-import "github.com/hashicorp/hcl/v2/hclwrite"
+import (
+	"slices"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
 
 // StartLine is the default line used when parsing HCL.
 const StartLine = 1
@@ -24,6 +28,10 @@ const IndexOffset = 1
 // IndexNotFound is the sentinel returned when no match is found.
 const IndexNotFound = -1
 
+// LabelSeparator joins block labels in Item.LabelKey. It sorts before any
+// other character, so label keys order by each label in turn.
+const LabelSeparator = "\x00"
+
 // ItemKind identifies whether an item is an attribute or a block.
 type ItemKind int
 
@@ -34,10 +42,107 @@ const (
 	ItemBlock
 )
 
+// FileKind identifies the kind of file being formatted, which selects
+// the ordering of its top-level blocks.
+type FileKind int
+
+const (
+	// FileConfig marks a configuration file (.tf).
+	FileConfig FileKind = iota
+	// FileTest marks a test file (.tftest.hcl).
+	FileTest
+	// FileMock marks a mock data file (.tfmock.hcl).
+	FileMock
+	// FileVariables marks a variable definitions file (.tfvars).
+	FileVariables
+)
+
+// Scope identifies an enclosing block by its type and labels.
+type Scope struct {
+	BlockType string
+	Labels    []string
+}
+
 // Context describes the current body scope.
 type Context struct {
 	Root      bool
 	BlockType string
+	Labels    []string
+	// Parents lists the enclosing blocks, outermost first.
+	Parents []Scope
+	// KeepOrder disables reordering in this body and every nested body.
+	KeepOrder bool
+	// File is the kind of the file being formatted.
+	File FileKind
+	// Dir is the directory of the file being formatted. It is empty when
+	// the file has no path, as on standard input.
+	Dir string
+	// Inputs lists the variables declared by the module whose inputs the
+	// body assigns, in declaration order. It is nil when the body assigns
+	// no module inputs or the declarations are unknown.
+	Inputs []string
+}
+
+// RootContext returns the context for the body of a file of kind file.
+func RootContext(file FileKind) Context {
+	return Context{
+		Root:      true,
+		BlockType: EmptyString,
+		Labels:    nil,
+		Parents:   nil,
+		KeepOrder: false,
+		File:      file,
+		Dir:       EmptyString,
+		Inputs:    nil,
+	}
+}
+
+// Scope returns the scope of the block that owns the current body.
+func (ctx Context) Scope() Scope {
+	return Scope{BlockType: ctx.BlockType, Labels: ctx.Labels}
+}
+
+// Child returns the context for the body of a nested block.
+func (ctx Context) Child(blockType string, labels []string) Context {
+	var parents []Scope
+	if !ctx.Root {
+		parents = append(slices.Clip(ctx.Parents), ctx.Scope())
+	}
+
+	return Context{
+		Root:      false,
+		BlockType: blockType,
+		Labels:    labels,
+		Parents:   parents,
+		KeepOrder: ctx.KeepOrder,
+		File:      ctx.File,
+		Dir:       ctx.Dir,
+		Inputs:    nil,
+	}
+}
+
+// Sibling returns the context of a block placed next to the current block,
+// in the same parent body.
+func (ctx Context) Sibling(blockType string, labels []string) Context {
+	return Context{
+		Root:      false,
+		BlockType: blockType,
+		Labels:    labels,
+		Parents:   ctx.Parents,
+		KeepOrder: ctx.KeepOrder,
+		File:      ctx.File,
+		Dir:       ctx.Dir,
+		Inputs:    nil,
+	}
+}
+
+// Path returns the enclosing scopes followed by the current scope.
+func (ctx Context) Path() []Scope {
+	if ctx.Root {
+		return nil
+	}
+
+	return append(slices.Clip(ctx.Parents), ctx.Scope())
 }
 
 // Item stores the tokens and metadata for a body element.
@@ -52,6 +157,10 @@ type Item struct {
 	OrigIndex int
 	Start     int
 	End       int
+	// Fixed items keep their position, prefix and body unchanged.
+	Fixed bool
+	// KeepOrder disables reordering inside the item's block body.
+	KeepOrder bool
 }
 
 // Items groups leading/trailing tokens with body items.
@@ -2,7 +2,9 @@
 package ordering
 
 import (
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/mreimbold/terraformat/internal/config"
 	"github.com/mreimbold/terraformat/internal/format/model"
@@ -17,6 +19,12 @@ type Key struct {
 	Index int
 }
 
+// keyedItem pairs an item with its precomputed ordering key.
+type keyedItem struct {
+	item model.Item
+	key  Key
+}
+
 const (
 	sortGroupDefault = iota
 )
@@ -34,45 +42,116 @@ const (
 	rootAttrOrderDefault = iota
 )
 
-type topLevelOrder int
+// blockRun is the block type of test runs, which are executed in order.
+const blockRun = "run"
 
+// Provisioners run in the order they are written, after the connection
+// block they use is declared.
 const (
-	topOrderTerraform topLevelOrder = iota
-	topOrderProvider
-	topOrderVariable
-	topOrderLocals
-	topOrderData
-	topOrderResource
-	topOrderModule
-	topOrderOutput
-	topOrderMoved
-	topOrderImport
-	topOrderCheck
-	topOrderAssert
+	blockConnection  = "connection"
+	blockProvisioner = "provisioner"
 )
 
-const topOrderUnknown topLevelOrder = 100
-
-const (
-	rootLabelVariable = "variable"
-	rootLabelOutput   = "output"
-)
-
-// SortItems sorts items using the configured ordering rules.
+// SortItems sorts items using the configured ordering rules. Fixed items
+// keep their position and split the items into runs sorted independently.
 func SortItems(items []model.Item, ctx model.Context, cfg config.Config) {
 	for itemIndex := range items {
 		items[itemIndex].OrigIndex = itemIndex
 	}
 
-	sort.SliceStable(items, func(leftIndex, rightIndex int) bool {
-		left := ItemSortKey(items[leftIndex], ctx, cfg)
-		right := ItemSortKey(items[rightIndex], ctx, cfg)
+	start := model.IndexFirst
+
+	for itemIndex, item := range items {
+		if item.Fixed {
+			sortRun(items[start:itemIndex], ctx, cfg)
+			start = itemIndex + model.IndexOffset
+		}
+	}
+
+	sortRun(items[start:], ctx, cfg)
+}
+
+func sortRun(items []model.Item, ctx model.Context, cfg config.Config) {
+	ranks := runRanks(items, ctx, cfg)
+
+	keyed := make([]keyedItem, model.IndexFirst, len(items))
+	for _, item := range items {
+		key := ItemSortKey(item, ctx, cfg)
+		key.Order += ranks[item.Name]
 
-		return lessKey(left, right)
+		keyed = append(keyed, keyedItem{item: item, key: key})
+	}
+
+	placeConnection(keyed, cfg.SortComparison)
+
+	sort.SliceStable(keyed, func(leftIndex, rightIndex int) bool {
+		return lessKey(
+			keyed[leftIndex].key,
+			keyed[rightIndex].key,
+			cfg.SortComparison,
+		)
 	})
+
+	for itemIndex := range keyed {
+		items[itemIndex] = keyed[itemIndex].item
+	}
 }
 
-func lessKey(left Key, right Key) bool {
+// placeConnection moves a connection block that would sort after the first
+// provisioner directly before it. Other blocks keep their keys, so the
+// provisioners stay where their profile puts them.
+func placeConnection(keyed []keyedItem, mode config.Comparison) {
+	first := model.IndexNotFound
+
+	for index, next := range keyed {
+		if isBlockNamed(next.item, blockProvisioner) &&
+			(first == model.IndexNotFound ||
+				lessKey(next.key, keyed[first].key, mode)) {
+			first = index
+		}
+	}
+
+	if first == model.IndexNotFound {
+		return
+	}
+
+	for index, next := range keyed {
+		if !isBlockNamed(next.item, blockConnection) ||
+			lessKey(next.key, keyed[first].key, mode) {
+			continue
+		}
+
+		key := keyed[first].key
+		key.Index -= model.IndexOffset
+		keyed[index].key = key
+	}
+}
+
+func isBlockNamed(item model.Item, name string) bool {
+	return item.Kind == model.ItemBlock && item.Name == name
+}
+
+// runRanks returns the topological rank of each attribute when the body's
+// profile orders its remaining names topologically, and nil otherwise.
+// Pinned and unranked names get no rank.
+func runRanks(
+	items []model.Item,
+	ctx model.Context,
+	cfg config.Config,
+) map[string]int {
+	if ctx.Root {
+		return nil
+	}
+
+	profile := profileFor(cfg, ctx)
+	if profile.Remaining != config.RemainingTopological {
+		return nil
+	}
+
+	return topologicalRanks(items, profile, cfg.SortComparison)
+}
+
+func lessKey(left Key, right Key, mode config.Comparison) bool {
 	if left.Group != right.Group {
 		return left.Group < right.Group
 	}
@@ -81,12 +160,14 @@ func lessKey(left Key, right Key) bool {
 		return left.Order < right.Order
 	}
 
-	if left.Name != right.Name {
-		return left.Name < right.Name
+	order := compareNames(left.Name, right.Name, mode)
+	if order != orderEqual {
+		return order < orderEqual
 	}
 
-	if left.Label != right.Label {
-		return left.Label < right.Label
+	order = compareNames(left.Label, right.Label, mode)
+	if order != orderEqual {
+		return order < orderEqual
 	}
 
 	return left.Index < right.Index
@@ -95,53 +176,64 @@ func lessKey(left Key, right Key) bool {
 // ItemSortKey returns the ordering key for the given item in context.
 func ItemSortKey(item model.Item, ctx model.Context, cfg config.Config) Key {
 	if ctx.Root {
-		return rootSortKey(item, cfg)
+		return rootSortKey(item, ctx, cfg)
 	}
 
-	sorter := blockSorter(ctx.BlockType)
-	if sorter == nil {
-		return defaultSortKey(item)
-	}
-
-	return sorter(item)
-}
-
-func blockSorter(blockType string) func(model.Item) Key {
-	sorters := map[string]func(model.Item) Key{
-		"resource":  resourceSortKey,
-		"data":      resourceSortKey,
-		"variable":  variableSortKey,
-		"output":    outputSortKey,
-		"module":    moduleSortKey,
-		"provider":  providerSortKey,
-		"terraform": terraformSortKey,
-		"locals":    localsSortKey,
-		"lifecycle": lifecycleSortKey,
-	}
-
-	return sorters[blockType]
+	return profileSortKey(item, profileFor(cfg, ctx), ctx.Inputs)
 }
 
-func rootSortKey(item model.Item, cfg config.Config) Key {
+func rootSortKey(item model.Item, ctx model.Context, cfg config.Config) Key {
 	key := newKey(item.OrigIndex)
 	if item.Kind == model.ItemAttribute {
-		return rootAttributeKey(item, cfg, key)
+		return rootAttributeKey(item, ctx, cfg, key)
+	}
+
+	switch ctx.File {
+	case model.FileTest:
+		return testBlockKey(item, cfg, key)
+	case model.FileMock:
+		return mockBlockKey(item, cfg, key)
+	case model.FileConfig, model.FileVariables:
 	}
 
 	return rootBlockKey(item, cfg, key)
 }
 
-func rootAttributeKey(item model.Item, cfg config.Config, key Key) Key {
+func rootAttributeKey(
+	item model.Item,
+	ctx model.Context,
+	cfg config.Config,
+	key Key,
+) Key {
 	key.Group = rootGroupAttributes
 
-	if cfg.EnforceAttributeOrder {
-		key.Order = rootAttrOrderDefault
-		key.Name = item.Name
+	if !cfg.EnforceAttributeOrder {
+		key.Order = item.OrigIndex
 
 		return key
 	}
 
-	key.Order = item.OrigIndex
+	if ctx.File == model.FileVariables &&
+		cfg.TfvarsOrder == config.TfvarsOrderDeclaration {
+		return declarationKey(item, ctx.Inputs, key)
+	}
+
+	key.Order = rootAttrOrderDefault
+	key.Name = item.Name
+
+	return key
+}
+
+// declarationKey orders the assignment item like the declarations in
+// inputs. Unknown names go last, and every name keeps its original order
+// when there are no declarations.
+func declarationKey(item model.Item, inputs []string, key Key) Key {
+	slot := slices.Index(inputs, item.Name)
+	if slot == model.IndexNotFound {
+		slot = len(inputs)
+	}
+
+	key.Order = slot
 
 	return key
 }
@@ -150,17 +242,17 @@ func rootBlockKey(item model.Item, cfg config.Config, key Key) Key {
 	key.Group = rootGroupBlocks
 
 	if cfg.EnforceBlockOrder {
-		order, ok := topLevelBlockOrder()[item.Name]
-		if ok {
-			key.Order = int(order)
-		} else {
-			key.Order = int(topOrderUnknown)
-		}
+		key.Order = topLevelBlockOrder(cfg.BlockOrder, item.Name)
 
-		if shouldSortRootLabels(item.Name) {
+		if slices.Contains(cfg.SortLabels, item.Name) {
 			key.Label = item.LabelKey
 		}
 
+		address, ok := blockAddress(item)
+		if ok {
+			key.Label = address
+		}
+
 		return key
 	}
 
@@ -169,6 +261,86 @@ func rootBlockKey(item model.Item, cfg config.Config, key Key) Key {
 	return key
 }
 
+// testBlockKey orders the top-level blocks of a test file by the test
+// block order, and overrides by their target address. Run blocks are never
+// sorted by label, so they keep their execution order.
+func testBlockKey(item model.Item, cfg config.Config, key Key) Key {
+	key.Group = rootGroupBlocks
+
+	if !cfg.EnforceBlockOrder {
+		key.Order = item.OrigIndex
+
+		return key
+	}
+
+	key.Order = topLevelBlockOrder(cfg.TestBlockOrder, item.Name)
+
+	if item.Name != blockRun && slices.Contains(cfg.SortLabels, item.Name) {
+		key.Label = item.LabelKey
+	}
+
+	address, ok := blockAddress(item)
+	if ok {
+		key.Label = address
+	}
+
+	return key
+}
+
+// mockBlockKey orders the top-level blocks of a mock data file by the mock
+// block order, mocks by the type they mock and overrides by their target
+// address.
+func mockBlockKey(item model.Item, cfg config.Config, key Key) Key {
+	key.Group = rootGroupBlocks
+
+	if !cfg.EnforceBlockOrder {
+		key.Order = item.OrigIndex
+
+		return key
+	}
+
+	key.Order = topLevelBlockOrder(cfg.MockBlockOrder, item.Name)
+	key.Label = item.LabelKey
+
+	address, ok := blockAddress(item)
+	if ok {
+		key.Label = address
+	}
+
+	return key
+}
+
+// blockAddress returns the address that orders blocks without labels: the
+// from address of moved and removed blocks, the to address of import
+// blocks and the target address of overrides.
+func blockAddress(item model.Item) (string, bool) {
+	name, ok := addressAttributes()[item.Name]
+	if !ok {
+		return model.EmptyString, false
+	}
+
+	attr := item.Block.Body().GetAttribute(name)
+	if attr == nil {
+		return model.EmptyString, false
+	}
+
+	address := attr.Expr().BuildTokens(nil).Bytes()
+
+	return strings.TrimSpace(string(address)), true
+}
+
+func addressAttributes() map[string]string {
+	return map[string]string{
+		"moved":   "from",
+		"import":  "to",
+		"removed": "from",
+		// Overrides of test files and mock data files.
+		"override_resource": "target",
+		"override_data":     "target",
+		"override_module":   "target",
+	}
+}
+
 func newKey(index int) Key {
 	return Key{
 		Group: sortGroupDefault,
@@ -179,28 +351,18 @@ func newKey(index int) Key {
 	}
 }
 
-func topLevelBlockOrder() map[string]topLevelOrder {
-	return map[string]topLevelOrder{
-		"terraform": topOrderTerraform,
-		"provider":  topOrderProvider,
-		"variable":  topOrderVariable,
-		"locals":    topOrderLocals,
-		"data":      topOrderData,
-		"resource":  topOrderResource,
-		"module":    topOrderModule,
-		"output":    topOrderOutput,
-		"moved":     topOrderMoved,
-		"import":    topOrderImport,
-		"check":     topOrderCheck,
-		"assert":    topOrderAssert,
+// topLevelBlockOrder returns the slot of blockType in order. Unlisted
+// types share the fallback slot, or go last when there is none.
+func topLevelBlockOrder(order []string, blockType string) int {
+	slot := slices.Index(order, blockType)
+	if slot != model.IndexNotFound {
+		return slot
 	}
-}
 
-func shouldSortRootLabels(blockType string) bool {
-	switch blockType {
-	case rootLabelVariable, rootLabelOutput:
-		return true
-	default:
-		return false
+	slot = slices.Index(order, config.BlockOrderFallback)
+	if slot != model.IndexNotFound {
+		return slot
 	}
+
+	return len(order)
 }
@@ -0,0 +1,87 @@
+package ordering
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/mreimbold/terraformat/internal/config"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+const (
+	digitFirst = '0'
+	digitLast  = '9'
+)
+
+const orderEqual = 0
+
+// compareNames compares names or label keys using the comparison mode.
+// Names that compare equal ignoring case or leading zeros fall back to a
+// byte-wise comparison, so the order stays deterministic.
+func compareNames(left string, right string, mode config.Comparison) int {
+	var order int
+
+	switch mode {
+	case config.ComparisonCaseInsensitive:
+		order = strings.Compare(strings.ToLower(left), strings.ToLower(right))
+	case config.ComparisonNatural:
+		order = compareNatural(left, right)
+	case config.ComparisonBytewise:
+	}
+
+	if order != orderEqual {
+		return order
+	}
+
+	return strings.Compare(left, right)
+}
+
+// compareNatural compares runs of digits by their numeric value and every
+// other run byte-wise.
+func compareNatural(left string, right string) int {
+	for left != "" && right != "" {
+		leftRun, leftRest := nextRun(left)
+		rightRun, rightRest := nextRun(right)
+
+		order := compareRuns(leftRun, rightRun)
+		if order != orderEqual {
+			return order
+		}
+
+		left, right = leftRest, rightRest
+	}
+
+	return cmp.Compare(len(left), len(right))
+}
+
+func compareRuns(left string, right string) int {
+	if !isDigit(left[model.IndexFirst]) || !isDigit(right[model.IndexFirst]) {
+		return strings.Compare(left, right)
+	}
+
+	left = strings.TrimLeft(left, string(digitFirst))
+	right = strings.TrimLeft(right, string(digitFirst))
+
+	order := cmp.Compare(len(left), len(right))
+	if order != orderEqual {
+		return order
+	}
+
+	return strings.Compare(left, right)
+}
+
+// nextRun splits name after its leading run of digits or non-digits.
+func nextRun(name string) (string, string) {
+	digits := isDigit(name[model.IndexFirst])
+	end := model.IndexOffset
+
+	for end < len(name) && isDigit(name[end]) == digits {
+		end++
+	}
+
+	return name[:end], name[end:]
+}
+
+func isDigit(char byte) bool {
+	return char >= digitFirst && char <= digitLast
+}
@@ -0,0 +1,175 @@
+package ordering
+
+import (
+	"slices"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/mreimbold/terraformat/internal/config"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+// localReference is the root name of references to locals.
+const localReference = "local"
+
+// topologicalRanks returns the position of each unpinned attribute of
+// items in an order where every local follows the locals it references.
+// It returns nil when the attributes reference each other in a cycle,
+// reference a local that items don't define, or can't be parsed, so the
+// caller keeps the original order.
+func topologicalRanks(
+	items []model.Item,
+	profile config.Profile,
+	mode config.Comparison,
+) map[string]int {
+	deps, ok := localDependencies(items)
+	if !ok {
+		return nil
+	}
+
+	// Pinned names are placed by the profile, so they are never ranked
+	// and references to them need no ordering.
+	var names []string
+
+	for _, item := range items {
+		if item.Kind == model.ItemAttribute && !pinned(profile, item.Name) {
+			names = append(names, item.Name)
+		}
+	}
+
+	for name, refs := range deps {
+		deps[name] = slices.DeleteFunc(refs, func(ref string) bool {
+			return pinned(profile, ref)
+		})
+	}
+
+	ranks := make(map[string]int, len(names))
+	for len(ranks) < len(names) {
+		next, found := nextReady(names, deps, ranks, profile.Ties, mode)
+		if !found {
+			return nil
+		}
+
+		ranks[next] = len(ranks)
+	}
+
+	return ranks
+}
+
+// nextReady returns the first name, by the ties order, whose dependencies
+// all have a rank.
+func nextReady(
+	names []string,
+	deps map[string][]string,
+	ranks map[string]int,
+	ties config.Remaining,
+	mode config.Comparison,
+) (string, bool) {
+	next := ""
+	found := false
+
+	for _, name := range names {
+		if _, done := ranks[name]; done || !allRanked(deps[name], ranks) {
+			continue
+		}
+
+		if !found || tieBefore(name, next, ties, mode) {
+			next = name
+			found = true
+		}
+	}
+
+	return next, found
+}
+
+func tieBefore(
+	name string,
+	other string,
+	ties config.Remaining,
+	mode config.Comparison,
+) bool {
+	if ties != config.RemainingAlphabetical {
+		// Names are visited in their original order.
+		return false
+	}
+
+	return compareNames(name, other, mode) < orderEqual
+}
+
+func pinned(profile config.Profile, name string) bool {
+	return slices.Contains(profile.First, name) ||
+		slices.Contains(profile.Last, name)
+}
+
+func allRanked(names []string, ranks map[string]int) bool {
+	for _, name := range names {
+		if _, ok := ranks[name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// localDependencies maps each attribute to the attributes of items it
+// references as local.<name>. It reports false for references to locals
+// that items don't define and for expressions that can't be parsed.
+func localDependencies(items []model.Item) (map[string][]string, bool) {
+	defined := make(map[string]bool, len(items))
+	for _, item := range items {
+		defined[item.Name] = item.Kind == model.ItemAttribute
+	}
+
+	deps := make(map[string][]string, len(items))
+
+	for _, item := range items {
+		if item.Kind != model.ItemAttribute {
+			continue
+		}
+
+		refs, ok := localReferences(item)
+		if !ok {
+			return nil, false
+		}
+
+		for _, ref := range refs {
+			if !defined[ref] {
+				return nil, false
+			}
+		}
+
+		deps[item.Name] = refs
+	}
+
+	return deps, true
+}
+
+func localReferences(item model.Item) ([]string, bool) {
+	src := item.Attr.Expr().BuildTokens(nil).Bytes()
+	startPos := hcl.Pos{
+		Line:   model.StartLine,
+		Column: model.StartColumn,
+		Byte:   model.StartByte,
+	}
+
+	expr, diags := hclsyntax.ParseExpression(src, "", startPos)
+	if diags.HasErrors() {
+		return nil, false
+	}
+
+	var refs []string
+
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() != localReference ||
+			len(traversal) <= model.IndexOffset {
+			continue
+		}
+
+		step, ok := traversal[model.IndexOffset].(hcl.TraverseAttr)
+		if ok {
+			refs = append(refs, step.Name)
+		}
+	}
+
+	return refs, true
+}
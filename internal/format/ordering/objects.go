@@ -0,0 +1,91 @@
+package ordering
+
+import (
+	"slices"
+
+	"github.com/mreimbold/terraformat/internal/config"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+const (
+	blockTerraform         = "terraform"
+	blockRequiredProviders = "required_providers"
+	blockModule            = "module"
+	attrProviders          = "providers"
+)
+
+// ObjectOrder returns the comparison that orders the keys of the object
+// assigned to the attribute name in the body described by ctx. Keys are
+// sorted alphabetically for the attributes in cfg.SortObjectKeys. It
+// reports false when the keys keep their order.
+func ObjectOrder(
+	name string,
+	ctx model.Context,
+	cfg config.Config,
+) (func(left string, right string) int, bool) {
+	if isRequiredProviders(ctx) {
+		return pinnedOrder(requiredProviderKeys()), true
+	}
+
+	if ctx.BlockType == blockModule && name == attrProviders ||
+		slices.Contains(cfg.SortObjectKeys, name) {
+		return func(left string, right string) int {
+			return compareNames(left, right, cfg.SortComparison)
+		}, true
+	}
+
+	return nil, false
+}
+
+// ListOrder returns the comparison that orders the elements of the list
+// assigned to the attribute name, which are addresses such as those of
+// depends_on. It reports false when the elements keep their order.
+func ListOrder(
+	name string,
+	cfg config.Config,
+) (func(left string, right string) int, bool) {
+	if !slices.Contains(cfg.SortLists, name) {
+		return nil, false
+	}
+
+	return func(left string, right string) int {
+		return compareNames(left, right, cfg.SortComparison)
+	}, true
+}
+
+// requiredProviderKeys returns the canonical order of the keys of a
+// required_providers entry.
+func requiredProviderKeys() []string {
+	return []string{"source", "version", "configuration_aliases"}
+}
+
+func isRequiredProviders(ctx model.Context) bool {
+	if ctx.BlockType != blockRequiredProviders {
+		return false
+	}
+
+	parents := ctx.Parents
+	//nolint:revive // add-constant: len check is clear here.
+	if len(parents) == 0 {
+		return false
+	}
+
+	return parents[len(parents)-model.IndexOffset].BlockType == blockTerraform
+}
+
+// pinnedOrder orders the names in pinned first, in that order, and keeps
+// the order of every other name.
+func pinnedOrder(pinned []string) func(left string, right string) int {
+	rank := func(name string) int {
+		index := slices.Index(pinned, name)
+		if index == model.IndexNotFound {
+			return len(pinned)
+		}
+
+		return index
+	}
+
+	return func(left string, right string) int {
+		return rank(left) - rank(right)
+	}
+}
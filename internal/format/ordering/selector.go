@@ -0,0 +1,111 @@
+package ordering
+
+import (
+	"strings"
+
+	"github.com/mreimbold/terraformat/internal/config"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+// selectorSegment matches one block by type and, optionally, first label.
+type selectorSegment struct {
+	blockType string
+	label     string
+}
+
+// selectorRank orders matching selectors from least to most specific.
+type selectorRank struct {
+	segments int
+	labels   int
+	key      string
+}
+
+// profileFor returns the most specific profile whose selector matches the
+// current block. Selectors are block types, optionally followed by a first
+// label ("resource.aws_instance"), joined by "/" to match nested blocks
+// ("resource.aws_instance/ebs_block_device").
+func profileFor(cfg config.Config, ctx model.Context) config.Profile {
+	path := ctx.Path()
+	best := cfg.Profiles[config.ProfileFallback]
+	bestRank := selectorRank{segments: 0, labels: 0, key: model.EmptyString}
+
+	for key, profile := range cfg.Profiles {
+		if key == config.ProfileFallback {
+			continue
+		}
+
+		rank, ok := matchSelector(key, path)
+		if ok && moreSpecific(rank, bestRank) {
+			best = profile
+			bestRank = rank
+		}
+	}
+
+	return best
+}
+
+// matchSelector reports whether the selector matches the innermost
+// scopes of path.
+func matchSelector(key string, path []model.Scope) (selectorRank, bool) {
+	segments := parseSelector(key)
+	rank := selectorRank{segments: len(segments), labels: 0, key: key}
+
+	offset := len(path) - len(segments)
+	if offset < model.IndexFirst {
+		return rank, false
+	}
+
+	for segmentIndex, segment := range segments {
+		if !segment.matches(path[offset+segmentIndex]) {
+			return rank, false
+		}
+
+		if segment.label != model.EmptyString {
+			rank.labels++
+		}
+	}
+
+	return rank, true
+}
+
+func parseSelector(key string) []selectorSegment {
+	parts := strings.Split(key, config.SelectorNesting)
+	segments := make([]selectorSegment, model.IndexFirst, len(parts))
+
+	for _, part := range parts {
+		blockType, label, _ := strings.Cut(part, config.SelectorLabel)
+		segments = append(segments, selectorSegment{
+			blockType: blockType,
+			label:     label,
+		})
+	}
+
+	return segments
+}
+
+func (segment selectorSegment) matches(scope model.Scope) bool {
+	if segment.blockType != scope.BlockType {
+		return false
+	}
+
+	if segment.label == model.EmptyString {
+		return true
+	}
+
+	//nolint:revive // add-constant: len check is clear here.
+	return len(scope.Labels) > 0 &&
+		scope.Labels[model.IndexFirst] == segment.label
+}
+
+func moreSpecific(candidate selectorRank, current selectorRank) bool {
+	if candidate.segments != current.segments {
+		return candidate.segments > current.segments
+	}
+
+	if candidate.labels != current.labels {
+		return candidate.labels > current.labels
+	}
+
+	// Equally specific selectors are resolved by key for determinism.
+	return current.key == model.EmptyString || candidate.key < current.key
+}
@@ -1,6 +1,9 @@
 package spacing
 
 import (
+	"slices"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mreimbold/terraformat/internal/config"
 	"github.com/mreimbold/terraformat/internal/format/model"
 	"github.com/mreimbold/terraformat/internal/format/ordering"
@@ -12,6 +15,31 @@ type group struct {
 	kind      model.ItemKind
 }
 
+// KeepsBlankLines reports whether the blank lines between items are left
+// as written: when spacing is not enforced and inside keep-order blocks.
+func KeepsBlankLines(ctx model.Context, cfg config.Config) bool {
+	return !cfg.EnforceTopLevelSpacing || ctx.KeepOrder
+}
+
+// KeptPrefixTokens returns the prefix of the item at index when blank
+// lines are kept as written. An item in its source position keeps its
+// prefix. An item that moved keeps its comments, and the blank lines stay
+// at the position they were written at.
+func KeptPrefixTokens(items []model.Item, index int) hclwrite.Tokens {
+	item := items[index]
+	if item.OrigIndex == index {
+		return item.Prefix
+	}
+
+	slot := slices.IndexFunc(items, func(other model.Item) bool {
+		return other.OrigIndex == index
+	})
+
+	prefix := slices.Clone(leadingNewlines(items[slot].Prefix))
+
+	return append(prefix, NormalizePrefixTokens(item.Prefix)...)
+}
+
 // ShouldInsertBlankLine reports whether a blank line should be inserted.
 func ShouldInsertBlankLine(
 	items []model.Item,
@@ -23,10 +51,6 @@ func ShouldInsertBlankLine(
 		return false
 	}
 
-	if !cfg.EnforceTopLevelSpacing {
-		return false
-	}
-
 	prev := items[index-model.IndexOffset]
 	current := items[index]
 
@@ -41,7 +65,12 @@ func ShouldInsertBlankLine(
 		return true
 	}
 
-	if prev.Kind != model.ItemBlock || current.Kind != model.ItemBlock {
+	// Bodies that mix blocks with attributes still separate them.
+	if prev.Kind != current.Kind {
+		return true
+	}
+
+	if prev.Kind != model.ItemBlock {
 		return false
 	}
 
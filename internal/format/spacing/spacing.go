@@ -82,6 +82,17 @@ func containsNewline(tokens hclwrite.Tokens) bool {
 	return false
 }
 
+// leadingNewlines returns the newlines before the first other token.
+func leadingNewlines(tokens hclwrite.Tokens) hclwrite.Tokens {
+	for tokenIndex, token := range tokens {
+		if token.Type != hclsyntax.TokenNewline {
+			return tokens[:tokenIndex]
+		}
+	}
+
+	return tokens
+}
+
 // ContainsComment reports whether tokens include any comments.
 func ContainsComment(tokens hclwrite.Tokens) bool {
 	for _, token := range tokens {
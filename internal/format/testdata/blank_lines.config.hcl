@@ -0,0 +1 @@
+style = "fmt"
@@ -0,0 +1,21 @@
+locals {
+  a = 1
+
+
+  b = 2
+
+  # Kept with its blank lines.
+
+
+
+  c = 3
+}
+
+
+
+resource "aws_instance" "web" {
+  ami  = "ami-123"
+
+
+  tags = {}
+}
@@ -0,0 +1 @@
+block_order = ["terraform", "locals", "variable", "output", "*", "module"]
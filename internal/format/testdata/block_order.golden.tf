@@ -0,0 +1,25 @@
+terraform {
+  required_version = ">= 1.6.0"
+}
+
+locals {
+  cidr = "10.0.0.0/16"
+}
+
+variable "name" {
+  type = string
+}
+
+output "vpc_id" {
+  value = aws_vpc.main.id
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = local.cidr
+}
+
+data "aws_region" "current" {}
+
+module "network" {
+  source = "./modules/network"
+}
@@ -0,0 +1,25 @@
+module "network" {
+  source = "./modules/network"
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = local.cidr
+}
+
+output "vpc_id" {
+  value = aws_vpc.main.id
+}
+
+variable "name" {
+  type = string
+}
+
+data "aws_region" "current" {}
+
+locals {
+  cidr = "10.0.0.0/16"
+}
+
+terraform {
+  required_version = ">= 1.6.0"
+}
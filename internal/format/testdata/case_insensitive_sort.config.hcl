@@ -0,0 +1 @@
+sort_comparison = "case-insensitive"
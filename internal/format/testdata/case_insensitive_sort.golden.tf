@@ -0,0 +1,4 @@
+app    = "web"
+bucket = "assets"
+Region = "eu-west-1"
+Zone   = "eu-west-1a"
@@ -0,0 +1,4 @@
+Zone    = "eu-west-1a"
+app     = "web"
+Region  = "eu-west-1"
+bucket  = "assets"
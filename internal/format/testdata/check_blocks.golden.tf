@@ -0,0 +1,39 @@
+variable "region" {
+  type = string
+
+  validation {
+    condition     = contains(["eu-west-1"], var.region)
+    error_message = "Unsupported region."
+  }
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-123"
+
+  lifecycle {
+    precondition {
+      condition     = self.ami != ""
+      error_message = "AMI must be set."
+    }
+
+    postcondition {
+      condition     = self.instance_state == "running"
+      error_message = "Instance must be running."
+    }
+  }
+}
+
+check "health" {
+  data "http" "site" {
+    url = "https://example.com"
+  }
+
+  assert {
+    condition     = data.http.site.status_code == 200
+    error_message = "Site is down."
+  }
+  assert {
+    condition     = length(data.http.site.response_body) > 0
+    error_message = "Body is empty."
+  }
+}
@@ -0,0 +1,40 @@
+check "health" {
+  assert {
+    error_message = "Site is down."
+    condition     = data.http.site.status_code == 200
+  }
+
+  data "http" "site" {
+    url = "https://example.com"
+  }
+
+  assert {
+    error_message = "Body is empty."
+    condition     = length(data.http.site.response_body) > 0
+  }
+}
+
+variable "region" {
+  type = string
+
+  validation {
+    error_message = "Unsupported region."
+    condition     = contains(["eu-west-1"], var.region)
+  }
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-123"
+
+  lifecycle {
+    postcondition {
+      error_message = "Instance must be running."
+      condition     = self.instance_state == "running"
+    }
+
+    precondition {
+      error_message = "AMI must be set."
+      condition     = self.ami != ""
+    }
+  }
+}
@@ -0,0 +1,56 @@
+output "b" {
+  value = 2
+}
+
+# terraformat:ignore
+resource "aws_security_group" "audited" {
+      count = 3
+  name = "audited"
+  egress {
+    protocol = "-1"
+  }
+  ingress {
+    protocol = "tcp"
+  }
+}
+
+# terraformat:keep-order
+resource "aws_security_group" "curated" {
+  name  = "curated"
+  count = 1
+
+
+  egress {
+    to_port   = 0
+    from_port = 0
+  }
+}
+
+output "a" {
+  value = 1
+}
+
+# terraformat:off
+variable "z" {
+    type      =   string
+  description = "z"
+}
+
+
+variable "y" {
+  default = "y"
+  type    = string
+}
+# terraformat:on
+
+variable "x" {
+  type    = string
+  default = "x"
+}
+
+locals {
+  b = 2
+  # terraformat:ignore
+  long_name   =    "kept"
+  a = 1
+}
@@ -0,0 +1,3 @@
+profile "resource.aws_security_group/ingress" {
+  first = ["description", "from_port", "to_port"]
+}
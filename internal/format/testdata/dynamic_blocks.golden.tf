@@ -0,0 +1,14 @@
+resource "aws_security_group" "this" {
+  name = "web"
+
+  dynamic "ingress" {
+    for_each = var.ingress
+    iterator = ingress
+
+    content {
+      description = ingress.value.description
+      from_port   = ingress.value.from
+      to_port     = ingress.value.to
+    }
+  }
+}
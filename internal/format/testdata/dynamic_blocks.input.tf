@@ -0,0 +1,13 @@
+resource "aws_security_group" "this" {
+  name = "web"
+
+  dynamic "ingress" {
+    content {
+      to_port     = ingress.value.to
+      description = ingress.value.description
+      from_port   = ingress.value.from
+    }
+    iterator = ingress
+    for_each = var.ingress
+  }
+}
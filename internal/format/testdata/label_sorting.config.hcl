@@ -0,0 +1 @@
+sort_labels = ["resource", "data", "module"]
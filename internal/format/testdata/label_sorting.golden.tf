@@ -0,0 +1,31 @@
+data "aws_ami" "amazon" {
+  most_recent = true
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+}
+
+resource "aws_instance" "api" {
+  ami = data.aws_ami.ubuntu.id
+}
+
+resource "aws_instance" "web" {
+  ami = data.aws_ami.ubuntu.id
+}
+
+resource "aws_instance_profile" "web" {
+  name = "web"
+}
+
+resource "aws_subnet" "public" {
+  vpc_id = module.vpc.id
+}
+
+module "app" {
+  source = "./modules/app"
+}
+
+module "vpc" {
+  source = "./modules/vpc"
+}
@@ -0,0 +1,31 @@
+module "vpc" {
+  source = "./modules/vpc"
+}
+
+resource "aws_subnet" "public" {
+  vpc_id = module.vpc.id
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+}
+
+resource "aws_instance" "web" {
+  ami = data.aws_ami.ubuntu.id
+}
+
+module "app" {
+  source = "./modules/app"
+}
+
+resource "aws_instance" "api" {
+  ami = data.aws_ami.ubuntu.id
+}
+
+data "aws_ami" "amazon" {
+  most_recent = true
+}
+
+resource "aws_instance_profile" "web" {
+  name = "web"
+}
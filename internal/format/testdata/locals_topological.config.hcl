@@ -0,0 +1,4 @@
+profile "locals" {
+  remaining = "topological"
+  ties      = "alphabetical"
+}
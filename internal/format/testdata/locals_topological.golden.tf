@@ -0,0 +1,24 @@
+locals {
+  common_tags = {
+    Team = "platform"
+  }
+  environment = "prod"
+  prefix      = "${local.environment}-app"
+  service     = "api"
+  # Full name of the service.
+  service_name = "${local.prefix}-${local.service}"
+  tags = merge(local.common_tags, {
+    Name = local.service_name
+  })
+}
+
+locals {
+  b = local.a
+  a = local.b
+  c = 1
+}
+
+locals {
+  z = local.environment
+  y = 1
+}
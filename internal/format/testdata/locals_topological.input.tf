@@ -0,0 +1,24 @@
+locals {
+  # Full name of the service.
+  service_name = "${local.prefix}-${local.service}"
+  tags = merge(local.common_tags, {
+    Name = local.service_name
+  })
+  service = "api"
+  common_tags = {
+    Team = "platform"
+  }
+  prefix = "${local.environment}-app"
+  environment = "prod"
+}
+
+locals {
+  b = local.a
+  a = local.b
+  c = 1
+}
+
+locals {
+  z = local.environment
+  y = 1
+}
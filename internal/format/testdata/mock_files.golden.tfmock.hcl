@@ -0,0 +1,47 @@
+mock_resource "aws_iam_role" {
+  override_during = plan
+  defaults = {
+    arn = "arn:aws:iam::123456789012:role/mock"
+  }
+}
+
+mock_resource "aws_s3_bucket" {
+  defaults = {
+    arn = "arn:aws:s3:::mock"
+  }
+}
+
+# Data sources read by every test.
+mock_data "aws_region" {
+  defaults = {
+    name = "eu-west-1"
+  }
+}
+
+override_resource {
+  target = aws_iam_role.app
+  values = {
+    arn = "arn:aws:iam::123456789012:role/app"
+  }
+}
+
+override_resource {
+  target = aws_s3_bucket.logs
+  values = {
+    arn = "arn:aws:s3:::logs"
+  }
+}
+
+override_data {
+  target = data.aws_caller_identity.current
+  values = {
+    account_id = "123456789012"
+  }
+}
+
+override_module {
+  target = module.network
+  outputs = {
+    vpc_id = "vpc-123"
+  }
+}
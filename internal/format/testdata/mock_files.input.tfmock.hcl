@@ -0,0 +1,47 @@
+override_resource {
+  values = {
+    arn = "arn:aws:s3:::logs"
+  }
+  target = aws_s3_bucket.logs
+}
+
+mock_resource "aws_s3_bucket" {
+  defaults = {
+    arn = "arn:aws:s3:::mock"
+  }
+}
+
+override_module {
+  outputs = {
+    vpc_id = "vpc-123"
+  }
+  target = module.network
+}
+
+# Data sources read by every test.
+mock_data "aws_region" {
+  defaults = {
+    name = "eu-west-1"
+  }
+}
+
+override_resource {
+  target = aws_iam_role.app
+  values = {
+    arn = "arn:aws:iam::123456789012:role/app"
+  }
+}
+
+mock_resource "aws_iam_role" {
+  override_during = plan
+  defaults = {
+    arn = "arn:aws:iam::123456789012:role/mock"
+  }
+}
+
+override_data {
+  values = {
+    account_id = "123456789012"
+  }
+  target = data.aws_caller_identity.current
+}
@@ -0,0 +1,3 @@
+profile "module" {
+  remaining = "declaration"
+}
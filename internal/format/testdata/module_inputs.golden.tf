@@ -0,0 +1,21 @@
+module "app" {
+  source = "./module_inputs_child"
+  tags = {
+    Team = "platform"
+  }
+  region         = "eu-west-1"
+  instance_count = 2
+  environment    = "prod"
+  # Not declared by the module.
+  legacy_flag = true
+
+  depends_on = [module.network]
+}
+
+# Remote sources keep the alphabetical order.
+module "network" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+  cidr    = "10.0.0.0/16"
+  name    = "main"
+}
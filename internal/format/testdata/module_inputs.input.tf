@@ -0,0 +1,23 @@
+module "app" {
+  source = "./module_inputs_child"
+
+  tags = {
+    Team = "platform"
+  }
+  environment    = "prod"
+  # Not declared by the module.
+  legacy_flag    = true
+  instance_count = 2
+  region         = "eu-west-1"
+
+  depends_on = [module.network]
+}
+
+# Remote sources keep the alphabetical order.
+module "network" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+
+  name = "main"
+  cidr = "10.0.0.0/16"
+}
@@ -0,0 +1,11 @@
+variable "region" {
+  type = string
+}
+
+variable "instance_count" {
+  type = number
+}
+
+variable "environment" {
+  type = string
+}
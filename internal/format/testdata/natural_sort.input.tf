@@ -0,0 +1,23 @@
+variable "subnet_10" {
+  type = string
+}
+
+variable "subnet_2" {
+  type = string
+}
+
+variable "subnet_1" {
+  type = string
+}
+
+output "zone_b" {
+  value = var.subnet_2
+}
+
+output "zone_a10" {
+  value = var.subnet_10
+}
+
+output "zone_a9" {
+  value = var.subnet_1
+}
@@ -8,14 +8,14 @@ terraform {
   required_version = ">= 1.3.9"
 
   required_providers {
-    azurerm = {
-      source  = "hashicorp/azurerm"
-      version = ">= 4.0.0"
-    }
     azurecaf = {
       source  = "aztfmod/azurecaf"
       version = ">= 1.2.28"
     }
+    azurerm = {
+      source  = "hashicorp/azurerm"
+      version = ">= 4.0.0"
+    }
   }
 }
 
@@ -0,0 +1,59 @@
+terraform {
+  required_version = ">= 1.10"
+
+  backend "s3" {
+    bucket = "state"
+  }
+
+  encryption {
+    method "aes_gcm" "default" {
+      keys = key_provider.pbkdf2.default
+    }
+  }
+}
+
+provider "aws" {
+  alias    = "by_region"
+  for_each = toset(var.regions)
+  region   = each.value
+}
+
+variable "db_password" {
+  type      = string
+  sensitive = true
+  ephemeral = true
+}
+
+variable "regions" {
+  type    = list(string)
+  default = ["eu-west-1", "us-east-1"]
+  const   = true
+}
+
+resource "aws_db_instance" "main" {
+  password_wo = ephemeral.random_password.db.result
+}
+
+ephemeral "random_password" "db" {
+  count = 1
+
+  length = 16
+}
+
+module "app" {
+  source = "./app"
+}
+
+output "token" {
+  description = "Database password."
+  value       = ephemeral.random_password.db.result
+  ephemeral   = true
+}
+
+removed {
+  from = aws_instance.legacy
+
+  lifecycle {
+    destroy = false
+  }
+}
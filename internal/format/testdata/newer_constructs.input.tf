@@ -0,0 +1,55 @@
+output "token" {
+  ephemeral   = true
+  value       = ephemeral.random_password.db.result
+  description = "Database password."
+}
+
+ephemeral "random_password" "db" {
+  length = 16
+  count  = 1
+}
+
+module "app" {
+  source = "./app"
+}
+
+resource "aws_db_instance" "main" {
+  password_wo = ephemeral.random_password.db.result
+}
+
+provider "aws" {
+  region   = each.value
+  for_each = toset(var.regions)
+  alias    = "by_region"
+}
+
+variable "regions" {
+  const   = true
+  type    = list(string)
+  default = ["eu-west-1", "us-east-1"]
+}
+
+variable "db_password" {
+  ephemeral = true
+  sensitive = true
+  type      = string
+}
+
+removed {
+  lifecycle {
+    destroy = false
+  }
+  from = aws_instance.legacy
+}
+
+terraform {
+  encryption {
+    method "aes_gcm" "default" {
+      keys = key_provider.pbkdf2.default
+    }
+  }
+  backend "s3" {
+    bucket = "state"
+  }
+  required_version = ">= 1.10"
+}
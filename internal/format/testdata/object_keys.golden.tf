@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+      # Pinned for the v5 migration.
+      version               = ">= 5.0" # upper bound soon
+      configuration_aliases = [aws.west]
+    }
+    google = { source = "hashicorp/google", version = "~> 5.0" }
+    # Random values.
+    random = {
+      source  = "hashicorp/random"
+      version = "~> 3.0"
+    }
+  }
+}
+
+module "network" {
+  source = "./network"
+  providers = {
+    # West region.
+    aws.west = aws.west
+    google   = google
+
+    aws = aws.east
+  }
+}
+
+module "app" {
+  source    = "./app"
+  providers = { aws = aws, random = random }
+}
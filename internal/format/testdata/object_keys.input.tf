@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    # Random values.
+    random = {
+      version = "~> 3.0"
+      source  = "hashicorp/random"
+    }
+    aws = {
+      configuration_aliases = [aws.west]
+      # Pinned for the v5 migration.
+      version = ">= 5.0" # upper bound soon
+      source  = "hashicorp/aws"
+    }
+    google = { version = "~> 5.0", source = "hashicorp/google" }
+  }
+}
+
+module "network" {
+  source = "./network"
+  providers = {
+    google = google
+    # West region.
+    aws.west = aws.west
+
+    aws = aws.east
+  }
+}
+
+module "app" {
+  source    = "./app"
+  providers = { random = random, aws = aws }
+}
@@ -0,0 +1,16 @@
+profile "variable" {
+  first = ["description", "type"]
+}
+
+profile "ebs_block_device" {
+  first     = ["device_name"]
+  last      = ["tags"]
+  remaining = "alphabetical"
+}
+
+profile "settings" {
+  first       = ["enabled"]
+  group_first = true
+  blocks      = "mixed"
+  remaining   = "alphabetical"
+}
@@ -0,0 +1,31 @@
+variable "name" {
+  description = "Name"
+  type        = string
+  default     = "app"
+}
+
+resource "aws_instance" "app" {
+  ami = "ami-123"
+
+  ebs_block_device {
+    device_name = "/dev/sdb"
+    encrypted   = true
+    volume_size = 10
+
+    tags = {}
+  }
+}
+
+resource "custom_thing" "app" {
+  settings {
+    enabled = true
+
+    alpha = true
+
+    rule {
+      name = "r"
+    }
+
+    zone = "a"
+  }
+}
@@ -0,0 +1,27 @@
+variable "name" {
+  default     = "app"
+  type        = string
+  description = "Name"
+}
+
+resource "aws_instance" "app" {
+  ami = "ami-123"
+
+  ebs_block_device {
+    volume_size = 10
+    tags        = {}
+    encrypted   = true
+    device_name = "/dev/sdb"
+  }
+}
+
+resource "custom_thing" "app" {
+  settings {
+    zone = "a"
+    rule {
+      name = "r"
+    }
+    alpha   = true
+    enabled = true
+  }
+}
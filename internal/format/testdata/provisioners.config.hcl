@@ -0,0 +1,4 @@
+# Provisioners keep their order even when the rest is sorted.
+profile "removed" {
+  remaining = "alphabetical"
+}
@@ -0,0 +1,53 @@
+resource "aws_instance" "web" {
+  ami = "ami-123"
+
+  # Used by the remote-exec provisioner.
+  connection {
+    type = "ssh"
+    host = self.public_ip
+  }
+
+  provisioner "remote-exec" {
+    on_failure = continue
+    inline     = ["sudo systemctl start app"]
+  }
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo destroyed"
+  }
+
+  root_block_device {
+    volume_size = 20
+  }
+
+  lifecycle {
+    create_before_destroy = true
+
+    precondition {
+      condition     = var.ami != ""
+      error_message = "AMI must be set."
+    }
+
+    postcondition {
+      condition     = self.public_ip != ""
+      error_message = "A public IP is required."
+    }
+    postcondition {
+      condition     = self.instance_state == "running"
+      error_message = "Instance must be running."
+    }
+  }
+}
+
+removed {
+  from = aws_instance.old
+
+  provisioner "remote-exec" {
+    when   = destroy
+    inline = ["sudo systemctl stop app"]
+  }
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo removed"
+  }
+}
@@ -0,0 +1,51 @@
+resource "aws_instance" "web" {
+  provisioner "remote-exec" {
+    inline     = ["sudo systemctl start app"]
+    on_failure = continue
+  }
+
+  ami = "ami-123"
+
+  lifecycle {
+    postcondition {
+      condition     = self.public_ip != ""
+      error_message = "A public IP is required."
+    }
+    precondition {
+      condition     = var.ami != ""
+      error_message = "AMI must be set."
+    }
+    create_before_destroy = true
+    postcondition {
+      condition     = self.instance_state == "running"
+      error_message = "Instance must be running."
+    }
+  }
+
+  provisioner "local-exec" {
+    command = "echo destroyed"
+    when    = destroy
+  }
+
+  # Used by the remote-exec provisioner.
+  connection {
+    type = "ssh"
+    host = self.public_ip
+  }
+
+  root_block_device {
+    volume_size = 20
+  }
+}
+
+removed {
+  provisioner "remote-exec" {
+    inline = ["sudo systemctl stop app"]
+    when   = destroy
+  }
+  provisioner "local-exec" {
+    command = "echo removed"
+    when    = destroy
+  }
+  from = aws_instance.old
+}
@@ -0,0 +1,32 @@
+moved {
+  from = aws_instance.web_10
+  to   = aws_instance.web[1]
+}
+
+moved {
+  from = aws_instance.web_2
+  to   = aws_instance.web[0]
+}
+
+import {
+  to       = aws_instance.imported
+  id       = "i-123"
+  provider = aws.west
+}
+
+removed {
+  from = aws_s3_bucket.old
+}
+
+removed {
+  from = module.legacy
+
+  lifecycle {
+    destroy = false
+  }
+
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo second"
+  }
+}
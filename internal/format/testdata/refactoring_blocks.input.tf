@@ -0,0 +1,30 @@
+removed {
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo second"
+  }
+  lifecycle {
+    destroy = false
+  }
+  from = module.legacy
+}
+
+moved {
+  to   = aws_instance.web[1]
+  from = aws_instance.web_10
+}
+
+import {
+  provider = aws.west
+  id       = "i-123"
+  to       = aws_instance.imported
+}
+
+moved {
+  to   = aws_instance.web[0]
+  from = aws_instance.web_2
+}
+
+removed {
+  from = aws_s3_bucket.old
+}
@@ -0,0 +1 @@
+enforce_top_level_spacing = false
@@ -0,0 +1,20 @@
+variable "region" {
+  type = string
+}
+
+
+# Moves with the locals.
+locals {
+  a = 1
+
+
+  b = 2
+}
+resource "aws_instance" "web" {
+  count = 2
+
+
+  ami = "ami-123"
+  # Moves with its attribute.
+  instance_type = "t3.micro"
+}
@@ -0,0 +1,20 @@
+resource "aws_instance" "web" {
+  ami = "ami-123"
+
+
+  count = 2
+  # Moves with its attribute.
+  instance_type = "t3.micro"
+}
+
+
+# Moves with the locals.
+locals {
+  a = 1
+
+
+  b = 2
+}
+variable "region" {
+  type = string
+}
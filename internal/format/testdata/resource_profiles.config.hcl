@@ -0,0 +1,13 @@
+profile "resource.aws_instance" {
+  first       = ["ami", "instance_type"]
+  group_first = false
+}
+
+profile "resource.aws_security_group" {
+  first = ["count", "for_each", "provider", "ingress", "egress"]
+}
+
+profile "resource.aws_instance/ebs_block_device" {
+  first     = ["device_name"]
+  remaining = "alphabetical"
+}
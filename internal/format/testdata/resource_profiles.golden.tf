@@ -0,0 +1,33 @@
+resource "aws_instance" "app" {
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+  tags          = {}
+
+  ebs_block_device {
+    device_name = "/dev/sdb"
+    encrypted   = true
+    volume_size = 10
+  }
+}
+
+resource "aws_launch_template" "app" {
+  ebs_block_device {
+    volume_size = 10
+    encrypted   = true
+    device_name = "/dev/sdb"
+  }
+}
+
+resource "aws_security_group" "app" {
+  count = 1
+
+  name = "app"
+
+  ingress {
+    protocol = "tcp"
+  }
+
+  egress {
+    protocol = "-1"
+  }
+}
@@ -0,0 +1,32 @@
+resource "aws_instance" "app" {
+  tags          = {}
+  instance_type = "t3.micro"
+  ami           = "ami-123"
+
+  ebs_block_device {
+    volume_size = 10
+    encrypted   = true
+    device_name = "/dev/sdb"
+  }
+}
+
+resource "aws_launch_template" "app" {
+  ebs_block_device {
+    volume_size = 10
+    encrypted   = true
+    device_name = "/dev/sdb"
+  }
+}
+
+resource "aws_security_group" "app" {
+  egress {
+    protocol = "-1"
+  }
+
+  name  = "app"
+  count = 1
+
+  ingress {
+    protocol = "tcp"
+  }
+}
@@ -0,0 +1,6 @@
+sort_lists = [
+  "depends_on",
+  "ignore_changes",
+  "replace_triggered_by",
+  "vpc_security_group_ids",
+]
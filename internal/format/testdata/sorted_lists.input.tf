@@ -0,0 +1,32 @@
+resource "aws_instance" "app" {
+  ami                    = "ami-123"
+  vpc_security_group_ids = [aws_security_group.web.id, aws_security_group.app.id, aws_security_group.web.id]
+  # Not listed, so the elements keep their order.
+  subnet_ids = [aws_subnet.b.id, aws_subnet.a.id]
+
+  lifecycle {
+    ignore_changes = [tags, ami, tags]
+    replace_triggered_by = [
+      null_resource.rotate.id,
+      # Rebuild when the image changes.
+      terraform_data.image,
+      aws_iam_role.app
+    ]
+  }
+
+  depends_on = [
+    aws_subnet.b,
+    aws_iam_role_policy.app, # Needs the policy before boot.
+    aws_subnet.a,
+    aws_subnet.b,
+
+    module.network,
+    aws_vpc.main,
+  ]
+}
+
+module "app" {
+  source = "./modules/app"
+
+  depends_on = [module.network, module.database]
+}
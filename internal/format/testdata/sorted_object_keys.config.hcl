@@ -0,0 +1 @@
+sort_object_keys = ["tags", "labels", "environment"]
@@ -0,0 +1,38 @@
+provider "aws" {
+  default_tags {
+    tags = { Owner = "ops", Team = "platform" }
+  }
+}
+
+resource "aws_instance" "app" {
+  ami = "ami-123"
+  tags = {
+    # Cost allocation.
+    CostCenter              = "1234"
+    Name                    = "app"
+    "kubernetes.io/cluster" = "shared" # Required by the cluster.
+
+    Backup      = "daily"
+    Environment = var.environment
+  }
+  # Not listed, so the keys keep their order.
+  metadata_options = {
+    http_tokens   = "required"
+    http_endpoint = "enabled"
+  }
+}
+
+resource "google_compute_instance" "app" {
+  labels = {
+    env  = "prod"
+    team = "platform"
+  }
+}
+
+module "worker" {
+  source = "./modules/worker"
+  environment = {
+    API_URL   = var.api_url
+    LOG_LEVEL = "info"
+  }
+}
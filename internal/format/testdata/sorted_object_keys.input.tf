@@ -0,0 +1,38 @@
+provider "aws" {
+  default_tags {
+    tags = { Team = "platform", Owner = "ops" }
+  }
+}
+
+resource "aws_instance" "app" {
+  ami = "ami-123"
+  tags = {
+    Name = "app"
+    # Cost allocation.
+    CostCenter = "1234"
+    "kubernetes.io/cluster" = "shared" # Required by the cluster.
+
+    Environment = var.environment
+    Backup      = "daily"
+  }
+  # Not listed, so the keys keep their order.
+  metadata_options = {
+    http_tokens   = "required"
+    http_endpoint = "enabled"
+  }
+}
+
+resource "google_compute_instance" "app" {
+  labels = {
+    team = "platform"
+    env  = "prod"
+  }
+}
+
+module "worker" {
+  source = "./modules/worker"
+  environment = {
+    LOG_LEVEL = "info"
+    API_URL   = var.api_url
+  }
+}
@@ -0,0 +1,48 @@
+test {
+  parallel = true
+}
+
+variables {
+  region = "eu-west-1"
+}
+
+provider "aws" {
+  region = var.region
+}
+
+mock_provider "aws" {
+  alias = "mock"
+}
+
+run "apply_bucket" {
+  command = apply
+
+  variables {
+    bucket_name = "logs"
+  }
+
+  assert {
+    condition     = aws_s3_bucket.this.bucket == "logs"
+    error_message = "Bucket name is wrong."
+  }
+}
+
+# Runs are executed in order, so they are never sorted.
+run "a_setup" {
+  module {
+    source = "./testing/setup"
+  }
+}
+
+run "invalid_name" {
+  command = plan
+  providers = {
+    aws = aws.mock
+  }
+
+  variables {
+    bucket_name = "INVALID"
+  }
+
+  expect_failures = [var.bucket_name]
+}
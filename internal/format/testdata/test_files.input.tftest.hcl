@@ -0,0 +1,45 @@
+run "apply_bucket" {
+  assert {
+    error_message = "Bucket name is wrong."
+    condition     = aws_s3_bucket.this.bucket == "logs"
+  }
+
+  variables {
+    bucket_name = "logs"
+  }
+  command = apply
+}
+
+variables {
+  region = "eu-west-1"
+}
+
+# Runs are executed in order, so they are never sorted.
+run "a_setup" {
+  module {
+    source = "./testing/setup"
+  }
+}
+
+mock_provider "aws" {
+  alias = "mock"
+}
+
+provider "aws" {
+  region = var.region
+}
+
+run "invalid_name" {
+  expect_failures = [var.bucket_name]
+  variables {
+    bucket_name = "INVALID"
+  }
+  providers = {
+    aws = aws.mock
+  }
+  command = plan
+}
+
+test {
+  parallel = true
+}
@@ -0,0 +1,2 @@
+tfvars_order  = "declaration"
+tfvars_module = "tfvars_module"
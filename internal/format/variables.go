@@ -0,0 +1,122 @@
+package format
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+const (
+	attrSource        = "source"
+	blockVariable     = "variable"
+	extensionConfig   = ".tf"
+	overrideFile      = "override.tf"
+	overrideFileTrail = "_override.tf"
+)
+
+// sourceTokens is the length of a quoted source without interpolations.
+const sourceTokens = 3
+
+// declaredVariables returns the names of the variables declared by the
+// module in dir, in the order Terraform loads them: files by name, then
+// blocks in source order. Override files only change declarations, so
+// they are skipped, and so are files that can't be read or parsed. It
+// returns nil when dir declares no variables.
+func declaredVariables(dir string) []string {
+	// ReadDir returns the entries sorted by file name.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isModuleFile(entry.Name()) {
+			continue
+		}
+
+		names = append(
+			names,
+			fileVariables(filepath.Join(dir, entry.Name()))...,
+		)
+	}
+
+	return names
+}
+
+// localSource returns the source of the module block when it is a local
+// path, which starts with ./ or ../ like in Terraform.
+func localSource(block *hclwrite.Block) (string, bool) {
+	attr := block.Body().GetAttribute(attrSource)
+	if attr == nil {
+		return model.EmptyString, false
+	}
+
+	exprTokens := attr.Expr().BuildTokens(nil)
+	if len(exprTokens) != sourceTokens ||
+		exprTokens[model.IndexOffset].Type != hclsyntax.TokenQuotedLit {
+		return model.EmptyString, false
+	}
+
+	source := string(exprTokens[model.IndexOffset].Bytes)
+	for _, prefix := range localPrefixes() {
+		if strings.HasPrefix(source, prefix) {
+			return source, true
+		}
+	}
+
+	return model.EmptyString, false
+}
+
+func localPrefixes() []string {
+	return []string{"./", "../"}
+}
+
+func isModuleFile(name string) bool {
+	if name == overrideFile || strings.HasSuffix(name, overrideFileTrail) {
+		return false
+	}
+
+	return strings.HasSuffix(name, extensionConfig)
+}
+
+func fileVariables(path string) []string {
+	//nolint:gosec // module paths come from the user's project.
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	startPos := hcl.Pos{
+		Line:   model.StartLine,
+		Column: model.StartColumn,
+		Byte:   model.StartByte,
+	}
+
+	file, diags := hclsyntax.ParseConfig(src, path, startPos)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+
+	for _, block := range body.Blocks {
+		if block.Type == blockVariable &&
+			len(block.Labels) == model.IndexOffset {
+			names = append(names, block.Labels[model.IndexFirst])
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,75 @@
+package format
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mreimbold/terraformat/internal/format/model"
+)
+
+// verbatimMarker starts the comment that stands in for a fixed item while
+// the file is written. Writing re-indents and re-aligns every token, so
+// fixed items only get their source back afterwards. The NUL byte keeps
+// the marker from matching a comment written by hand.
+const verbatimMarker = "#\x00terraformat:verbatim:"
+
+// verbatimToken returns a comment line that holds the source of parts.
+func verbatimToken(parts ...hclwrite.Tokens) *hclwrite.Token {
+	var source []byte
+	for _, part := range parts {
+		source = append(source, part.Bytes()...)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(source)
+
+	return &hclwrite.Token{
+		Type:  hclsyntax.TokenComment,
+		Bytes: []byte(verbatimMarker + encoded + "\n"),
+		//nolint:revive // add-constant: the source keeps its indentation.
+		SpacesBefore: 0,
+	}
+}
+
+// restoreVerbatim replaces each line written for a verbatimToken with the
+// source it holds.
+func restoreVerbatim(out []byte) []byte {
+	if !bytes.Contains(out, []byte(verbatimMarker)) {
+		return out
+	}
+
+	restored := make([]byte, model.IndexFirst, len(out))
+
+	for _, line := range bytes.SplitAfter(out, []byte("\n")) {
+		source, ok := verbatimSource(line)
+		if !ok {
+			restored = append(restored, line...)
+
+			continue
+		}
+
+		restored = append(restored, source...)
+	}
+
+	return restored
+}
+
+func verbatimSource(line []byte) ([]byte, bool) {
+	encoded, ok := bytes.CutPrefix(
+		bytes.TrimLeft(line, " "),
+		[]byte(verbatimMarker),
+	)
+	if !ok {
+		return nil, false
+	}
+
+	source, err := base64.StdEncoding.DecodeString(
+		string(bytes.TrimSuffix(encoded, []byte("\n"))),
+	)
+	if err != nil {
+		return nil, false
+	}
+
+	return source, true
+}